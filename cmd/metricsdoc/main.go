@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+func open(url, file string) (io.ReadCloser, error) {
+
+	if len(url) > 0 {
+
+		client := &http.Client{Timeout: 10 * time.Second}
+
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status fetching catalog: %s", resp.Status)
+		}
+
+		return resp.Body, nil
+
+	}
+
+	if len(file) > 0 && file != "-" {
+		return os.Open(file)
+	}
+
+	return io.NopCloser(os.Stdin), nil
+
+}
+
+func main() {
+
+	url := flag.String("url", "", "URL of a running service's metric catalog endpoint")
+	file := flag.String("file", "-", "path to a JSON metric catalog, or - for stdin")
+	title := flag.String("title", "Metrics", "heading of the generated document")
+
+	flag.Parse()
+
+	reader, err := open(*url, *file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	defer reader.Close()
+
+	descriptions, err := metrics.ReadCatalog(reader)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("# %s\n\n", *title)
+
+	if err := metrics.WriteCatalogMarkdown(os.Stdout, descriptions); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+}
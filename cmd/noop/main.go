@@ -7,6 +7,7 @@ import (
 	"github.com/definancialbr/golang-container-kit/pkg/container"
 	"github.com/definancialbr/golang-container-kit/pkg/logging/zap"
 	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+	"github.com/definancialbr/golang-container-kit/pkg/notify/systemd"
 	"github.com/definancialbr/golang-container-kit/pkg/probes/healthcheck"
 	"github.com/definancialbr/golang-container-kit/pkg/signaler"
 )
@@ -38,10 +39,14 @@ func main() {
 	cont.Metrics = prometheus.NewMetricService()
 
 	cont.Probes = healthcheck.NewProbeService(
-		healthcheck.WithDNSResolveCheckForLiveness("google-is-resolvable", "google.com", 10*time.Second),
+		healthcheck.WithDNSResolveCheckForLiveness("google-is-resolvable", "google.com", 10*time.Second, healthcheck.WithFailureThreshold(3)),
 		healthcheck.WithTCPDialCheckForReadiness("google-is-reachable", "google.com", 10*time.Second),
 	)
 
+	cont.Notifier = systemd.NewNotifierService(
+		systemd.WithLogging(cont.Logging),
+	)
+
 	hangupHandler := func(func()) error {
 		cont.Logging.Debug("O grande problema que a nação está enfrentando hoje é a falta de amor!")
 		return nil
@@ -63,6 +68,8 @@ func main() {
 		signaler.WithOnHangup(hangupHandler),
 		signaler.WithOnTermination(terminationHandler),
 		signaler.WithOnInterrupt(interruptHandler),
+		signaler.WithLogging(cont.Logging),
+		signaler.WithMetrics(cont.Metrics),
 	)
 
 	cont.Open()
@@ -8,6 +8,7 @@ type ConfigurationService interface {
 }
 
 type Loader interface {
+	AllSettings() map[string]interface{}
 	Get(string) interface{}
 	GetBool(string) bool
 	GetDuration(string) time.Duration
@@ -1,9 +1,14 @@
 package container
 
 import (
+	"context"
+	"io"
+
 	"github.com/definancialbr/golang-container-kit/pkg/configuration"
+	"github.com/definancialbr/golang-container-kit/pkg/listeners"
 	"github.com/definancialbr/golang-container-kit/pkg/logging"
 	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/notify"
 	"github.com/definancialbr/golang-container-kit/pkg/probes"
 	"github.com/definancialbr/golang-container-kit/pkg/signaler"
 )
@@ -21,9 +26,12 @@ type Container struct {
 	Metrics       metrics.MetricService
 	Signaler      signaler.SignalerService
 	Probes        probes.ProbeService
+	Notifier      notify.NotifierService
+	Listeners     listeners.ListenerService
 
 	configurationState ContainerServiceState
 	loggingState       ContainerServiceState
+	closing            chan struct{}
 }
 
 func NewContainer() *Container {
@@ -55,10 +63,83 @@ func (c *Container) Open() {
 
 	}
 
+	if c.Listeners != nil {
+
+		if err := c.Listeners.Ready(); err != nil {
+			panic(err)
+		}
+
+	}
+
+	if c.Notifier != nil {
+		c.openNotifier()
+	}
+
+}
+
+func (c *Container) openNotifier() {
+
+	if c.Probes != nil {
+		c.Notifier.StartWatchdog(func() error {
+			return probes.CheckLiveness(c.Probes)
+		})
+	}
+
+	if c.Signaler != nil && c.closing == nil {
+
+		c.closing = make(chan struct{})
+
+		go func(ctx context.Context, closing chan struct{}) {
+			select {
+			case <-ctx.Done():
+				c.Notifier.Status("shutting down")
+				c.Notifier.Stopping()
+			case <-closing:
+			}
+		}(c.Signaler.Context(), c.closing)
+
+	}
+
+	c.notify(c.Notifier.Status("running"))
+	c.notify(c.Notifier.Ready())
+
+}
+
+func (c *Container) notify(err error) {
+	if err != nil && c.loggingState == Open {
+		c.Logging.Warn("failed to notify service manager", "error", err.Error())
+	}
 }
 
 func (c *Container) Close() {
 
+	if c.closing != nil {
+		close(c.closing)
+		c.closing = nil
+	}
+
+	if c.Notifier != nil {
+		c.notify(c.Notifier.Status("stopping"))
+		c.notify(c.Notifier.Stopping())
+		c.notify(c.Notifier.Close())
+	}
+
+	if closer, ok := c.Metrics.(io.Closer); ok {
+
+		if err := closer.Close(); err != nil && c.loggingState == Open {
+			c.Logging.Error("failed to close metrics", "error", err.Error())
+		}
+
+	}
+
+	if closer, ok := c.Signaler.(io.Closer); ok {
+
+		if err := closer.Close(); err != nil && c.loggingState == Open {
+			c.Logging.Error("failed to close signaler", "error", err.Error())
+		}
+
+	}
+
 	if c.loggingState == Open {
 
 		if err := c.Logging.Close(); err != nil {
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+const (
+	ErrorOutcome = "error"
+)
+
+var (
+	DefaultRetryBackoff    = 100 * time.Millisecond
+	DefaultRetryMaxBackoff = 5 * time.Second
+)
+
+func DefaultRetryPolicy(resp *http.Response, err error) bool {
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+
+}
+
+func IsIdempotent(req *http.Request) bool {
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return len(req.Header.Get("Idempotency-Key")) > 0
+
+}
+
+type TransportOption func(*Transport)
+
+type Transport struct {
+	base            http.RoundTripper
+	logging         logging.LoggingService
+	namespace       string
+	subsystem       string
+	durationBuckets []float64
+	timeout         time.Duration
+	hostTimeouts    map[string]time.Duration
+	retries         int
+	backoff         time.Duration
+	maxBackoff      time.Duration
+	retryPolicy     func(*http.Response, error) bool
+	requests        metrics.Counter
+	duration        metrics.Histogram
+	retried         metrics.Counter
+}
+
+func WithBaseTransport(base http.RoundTripper) TransportOption {
+	return func(t *Transport) {
+		t.base = base
+	}
+}
+
+func WithClientNamespace(namespace string) TransportOption {
+	return func(t *Transport) {
+		t.namespace = namespace
+	}
+}
+
+func WithClientSubsystem(subsystem string) TransportOption {
+	return func(t *Transport) {
+		t.subsystem = subsystem
+	}
+}
+
+func WithClientDurationBuckets(buckets []float64) TransportOption {
+	return func(t *Transport) {
+		t.durationBuckets = buckets
+	}
+}
+
+func WithTimeout(timeout time.Duration) TransportOption {
+	return func(t *Transport) {
+		t.timeout = timeout
+	}
+}
+
+func WithHostTimeout(host string, timeout time.Duration) TransportOption {
+	return func(t *Transport) {
+		t.hostTimeouts[host] = timeout
+	}
+}
+
+func WithRetries(retries int) TransportOption {
+	return func(t *Transport) {
+		t.retries = retries
+	}
+}
+
+func WithRetryBackoff(backoff, maxBackoff time.Duration) TransportOption {
+	return func(t *Transport) {
+		t.backoff = backoff
+		t.maxBackoff = maxBackoff
+	}
+}
+
+func WithRetryPolicy(retryPolicy func(*http.Response, error) bool) TransportOption {
+	return func(t *Transport) {
+		t.retryPolicy = retryPolicy
+	}
+}
+
+func NewTransport(metricService metrics.MetricService, loggingService logging.LoggingService, options ...TransportOption) *Transport {
+
+	t := &Transport{
+		base:            http.DefaultTransport,
+		logging:         loggingService,
+		subsystem:       "http_client",
+		durationBuckets: DefaultDurationBuckets,
+		hostTimeouts:    make(map[string]time.Duration),
+		backoff:         DefaultRetryBackoff,
+		maxBackoff:      DefaultRetryMaxBackoff,
+		retryPolicy:     DefaultRetryPolicy,
+	}
+
+	for _, option := range options {
+		option(t)
+	}
+
+	labels := []string{"host", "method", "outcome"}
+
+	t.requests = metricService.Counter(
+		metrics.WithNamespace(t.namespace),
+		metrics.WithSubsystem(t.subsystem),
+		metrics.WithName("requests_total"),
+		metrics.WithHelp("Total number of outbound HTTP requests attempted."),
+		metrics.WithLabels(labels),
+	)
+
+	t.duration = metricService.Histogram(
+		metrics.WithNamespace(t.namespace),
+		metrics.WithSubsystem(t.subsystem),
+		metrics.WithName("request_duration_seconds"),
+		metrics.WithHelp("Duration of outbound HTTP requests in seconds."),
+		metrics.WithBuckets(t.durationBuckets),
+		metrics.WithLabels(labels),
+	)
+
+	t.retried = metricService.Counter(
+		metrics.WithNamespace(t.namespace),
+		metrics.WithSubsystem(t.subsystem),
+		metrics.WithName("retries_total"),
+		metrics.WithHelp("Total number of outbound HTTP requests retried."),
+		metrics.WithLabels([]string{"host", "method"}),
+	)
+
+	return t
+
+}
+
+func NewClient(metricService metrics.MetricService, loggingService logging.LoggingService, options ...TransportOption) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(metricService, loggingService, options...),
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	host := req.URL.Host
+	retries := t.retries
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		retries = 0
+	}
+
+	if !IsIdempotent(req) {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		resp, err := t.roundTrip(req, host, attempt)
+
+		if attempt >= retries || !t.retryPolicy(resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t.retried.WithLabelValues(host, req.Method).Add(1)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoffFor(attempt)):
+		}
+
+	}
+
+}
+
+func (t *Transport) roundTrip(req *http.Request, host string, attempt int) (*http.Response, error) {
+
+	ctx := req.Context()
+	cancel := context.CancelFunc(func() {})
+
+	if timeout := t.timeoutFor(req); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	outbound := req.Clone(ctx)
+	InjectPropagatedHeaders(ctx, outbound.Header)
+
+	if attempt > 0 && req.GetBody != nil {
+
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		outbound.Body = body
+
+	}
+
+	start := time.Now()
+
+	resp, err := t.base.RoundTrip(outbound)
+
+	elapsed := time.Since(start)
+
+	outcome := ErrorOutcome
+	if err == nil {
+		outcome = StatusClass(resp.StatusCode)
+	}
+
+	t.requests.WithLabelValues(host, req.Method, outcome).AddWithContext(ctx, 1)
+	t.duration.WithLabelValues(host, req.Method, outcome).ObserveWithContext(ctx, elapsed.Seconds())
+
+	if err != nil {
+
+		cancel()
+
+		if t.logging != nil {
+			t.logging.Warn("http client request failed",
+				"method", req.Method,
+				"host", host,
+				"path", req.URL.Path,
+				"attempt", attempt+1,
+				"duration", elapsed.String(),
+				"request_id", outbound.Header.Get(RequestIDHeader),
+				"error", err.Error(),
+			)
+		}
+
+		return nil, err
+
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError && t.logging != nil {
+		t.logging.Warn("http client request returned server error",
+			"method", req.Method,
+			"host", host,
+			"path", req.URL.Path,
+			"attempt", attempt+1,
+			"status", resp.StatusCode,
+			"duration", elapsed.String(),
+			"request_id", outbound.Header.Get(RequestIDHeader),
+		)
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+
+}
+
+func (t *Transport) timeoutFor(req *http.Request) time.Duration {
+
+	if timeout, ok := t.hostTimeouts[req.URL.Host]; ok {
+		return timeout
+	}
+
+	if timeout, ok := t.hostTimeouts[req.URL.Hostname()]; ok {
+		return timeout
+	}
+
+	return t.timeout
+
+}
+
+func (t *Transport) backoffFor(attempt int) time.Duration {
+
+	backoff := t.backoff << uint(attempt)
+	if backoff <= 0 || backoff > t.maxBackoff {
+		backoff = t.maxBackoff
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
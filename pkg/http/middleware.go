@@ -0,0 +1,297 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/gorilla/mux"
+)
+
+const (
+	UnmatchedRoute = "unmatched"
+)
+
+var (
+	DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	DefaultSizeBuckets     = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+)
+
+type routeHolderKey struct{}
+
+type routeHolder struct {
+	route string
+}
+
+func MuxRouteTemplate(r *http.Request) string {
+
+	if holder, ok := r.Context().Value(routeHolderKey{}).(*routeHolder); ok && len(holder.route) > 0 {
+		return holder.route
+	}
+
+	return muxRouteTemplate(r)
+
+}
+
+func muxRouteTemplate(r *http.Request) string {
+
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return UnmatchedRoute
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return UnmatchedRoute
+	}
+
+	return template
+
+}
+
+func RecordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if holder, ok := r.Context().Value(routeHolderKey{}).(*routeHolder); ok {
+			holder.route = muxRouteTemplate(r)
+		}
+
+		next.ServeHTTP(w, r)
+
+	})
+}
+
+func StatusClass(status int) string {
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+type MiddlewareOption func(*Middleware)
+
+type Middleware struct {
+	logging         logging.LoggingService
+	namespace       string
+	subsystem       string
+	durationBuckets []float64
+	sizeBuckets     []float64
+	routeFunc       func(*http.Request) string
+	accessLog       bool
+	propagated      []string
+	requests        metrics.Counter
+	duration        metrics.Histogram
+	inFlight        metrics.Gauge
+	responseSize    metrics.Histogram
+}
+
+func WithNamespace(namespace string) MiddlewareOption {
+	return func(m *Middleware) {
+		m.namespace = namespace
+	}
+}
+
+func WithSubsystem(subsystem string) MiddlewareOption {
+	return func(m *Middleware) {
+		m.subsystem = subsystem
+	}
+}
+
+func WithDurationBuckets(buckets []float64) MiddlewareOption {
+	return func(m *Middleware) {
+		m.durationBuckets = buckets
+	}
+}
+
+func WithSizeBuckets(buckets []float64) MiddlewareOption {
+	return func(m *Middleware) {
+		m.sizeBuckets = buckets
+	}
+}
+
+func WithRouteFunc(routeFunc func(*http.Request) string) MiddlewareOption {
+	return func(m *Middleware) {
+		m.routeFunc = routeFunc
+	}
+}
+
+func WithPropagatedHeaders(names ...string) MiddlewareOption {
+	return func(m *Middleware) {
+		m.propagated = names
+	}
+}
+
+func WithoutAccessLog() MiddlewareOption {
+	return func(m *Middleware) {
+		m.accessLog = false
+	}
+}
+
+func NewMiddleware(metricService metrics.MetricService, loggingService logging.LoggingService, options ...MiddlewareOption) *Middleware {
+
+	m := &Middleware{
+		logging:         loggingService,
+		subsystem:       "http_server",
+		durationBuckets: DefaultDurationBuckets,
+		sizeBuckets:     DefaultSizeBuckets,
+		routeFunc:       MuxRouteTemplate,
+		accessLog:       true,
+		propagated:      DefaultPropagatedHeaders,
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	labels := []string{"route", "method", "status"}
+
+	m.requests = metricService.Counter(
+		metrics.WithNamespace(m.namespace),
+		metrics.WithSubsystem(m.subsystem),
+		metrics.WithName("requests_total"),
+		metrics.WithHelp("Total number of HTTP requests handled."),
+		metrics.WithLabels(labels),
+	)
+
+	m.duration = metricService.Histogram(
+		metrics.WithNamespace(m.namespace),
+		metrics.WithSubsystem(m.subsystem),
+		metrics.WithName("request_duration_seconds"),
+		metrics.WithHelp("Duration of HTTP requests in seconds."),
+		metrics.WithBuckets(m.durationBuckets),
+		metrics.WithLabels(labels),
+	)
+
+	m.inFlight = metricService.Gauge(
+		metrics.WithNamespace(m.namespace),
+		metrics.WithSubsystem(m.subsystem),
+		metrics.WithName("requests_in_flight"),
+		metrics.WithHelp("Number of HTTP requests currently being handled."),
+		metrics.WithLabels([]string{"method"}),
+	)
+
+	m.responseSize = metricService.Histogram(
+		metrics.WithNamespace(m.namespace),
+		metrics.WithSubsystem(m.subsystem),
+		metrics.WithName("response_size_bytes"),
+		metrics.WithHelp("Size of HTTP responses in bytes."),
+		metrics.WithBuckets(m.sizeBuckets),
+		metrics.WithLabels(labels),
+	)
+
+	return m
+
+}
+
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		start := time.Now()
+
+		propagated := ExtractPropagatedHeaders(r.Header, m.propagated)
+		if len(propagated.Get(RequestIDHeader)) == 0 {
+			propagated.Set(RequestIDHeader, NewRequestID())
+		}
+
+		requestID := propagated.Get(RequestIDHeader)
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := ContextWithPropagatedHeaders(r.Context(), propagated)
+		ctx = context.WithValue(ctx, routeHolderKey{}, &routeHolder{})
+
+		r = r.WithContext(ctx)
+
+		inFlight := m.inFlight.WithLabelValues(r.Method)
+		inFlight.Add(1)
+		defer inFlight.Sub(1)
+
+		recorder := NewResponseRecorder(w)
+
+		next.ServeHTTP(recorder, r)
+
+		elapsed := time.Since(start)
+		route := m.routeFunc(r)
+		status := StatusClass(recorder.Status())
+
+		m.requests.WithLabelValues(route, r.Method, status).AddWithContext(r.Context(), 1)
+		m.duration.WithLabelValues(route, r.Method, status).ObserveWithContext(r.Context(), elapsed.Seconds())
+		m.responseSize.WithLabelValues(route, r.Method, status).Observe(float64(recorder.Size()))
+
+		if m.accessLog && m.logging != nil {
+			m.logging.Info("http request",
+				"method", r.Method,
+				"route", route,
+				"path", r.URL.Path,
+				"status", recorder.Status(),
+				"size", recorder.Size(),
+				"duration", elapsed.String(),
+				"remote_addr", r.RemoteAddr,
+				"user_agent", r.UserAgent(),
+				"request_id", requestID,
+			)
+		}
+
+	})
+}
+
+func (m *Middleware) Instrument(router *mux.Router) http.Handler {
+
+	router.Use(RecordRoute)
+
+	return m.Handler(router)
+
+}
+
+type ResponseRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{
+		ResponseWriter: w,
+		status:         http.StatusOK,
+	}
+}
+
+func (r *ResponseRecorder) WriteHeader(status int) {
+
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+
+}
+
+func (r *ResponseRecorder) Write(b []byte) (int, error) {
+
+	r.wroteHeader = true
+
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+
+	return n, err
+
+}
+
+func (r *ResponseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *ResponseRecorder) Status() int {
+	return r.status
+}
+
+func (r *ResponseRecorder) Size() int {
+	return r.size
+}
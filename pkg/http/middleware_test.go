@@ -0,0 +1,98 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/metrictest"
+	"github.com/gorilla/mux"
+)
+
+const requestsTotal = "http_server_requests_total"
+
+func newRouter() *mux.Router {
+
+	router := mux.NewRouter()
+
+	router.HandleFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(mux.Vars(r)["id"]))
+	}).Methods(http.MethodGet)
+
+	return router
+
+}
+
+func serve(handler http.Handler, method, path string) int {
+
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+
+	return recorder.Code
+
+}
+
+func assertRequests(t *testing.T, m *metrictest.MetricService, expected float64, keysAndValues ...string) {
+
+	t.Helper()
+
+	if value := m.CounterValue(requestsTotal, keysAndValues...); value != expected {
+		t.Errorf("expected %g requests for %v, got %g (series %v)", expected, keysAndValues, value, m.Series(requestsTotal))
+	}
+
+}
+
+func TestInstrumentLabelsRouteTemplates(t *testing.T) {
+
+	m := metrictest.NewMetricService()
+
+	handler := NewMiddleware(m, nil).Instrument(newRouter())
+
+	if status := serve(handler, http.MethodGet, "/users/42"); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	serve(handler, http.MethodGet, "/missing")
+	serve(handler, http.MethodPost, "/users/42")
+
+	assertRequests(t, m, 1, "route", "/users/{id}", "method", http.MethodGet, "status", "2xx")
+	assertRequests(t, m, 1, "route", UnmatchedRoute, "method", http.MethodGet, "status", "4xx")
+	assertRequests(t, m, 1, "route", UnmatchedRoute, "method", http.MethodPost, "status", "4xx")
+
+}
+
+func TestHandlerWithRecordRouteHook(t *testing.T) {
+
+	m := metrictest.NewMetricService()
+
+	router := newRouter()
+	router.Use(RecordRoute)
+
+	handler := NewMiddleware(m, nil).Handler(router)
+
+	serve(handler, http.MethodGet, "/users/42")
+	serve(handler, http.MethodGet, "/users/43")
+	serve(handler, http.MethodGet, "/missing")
+
+	assertRequests(t, m, 2, "route", "/users/{id}", "status", "2xx")
+	assertRequests(t, m, 1, "route", UnmatchedRoute, "status", "4xx")
+
+}
+
+func TestHandlerAsRouterMiddleware(t *testing.T) {
+
+	m := metrictest.NewMetricService()
+
+	router := newRouter()
+	router.Use(NewMiddleware(m, nil).Handler)
+
+	serve(router, http.MethodGet, "/users/42")
+
+	assertRequests(t, m, 1, "route", "/users/{id}", "method", http.MethodGet, "status", "2xx")
+
+	if value := m.CounterValue(requestsTotal, "route", UnmatchedRoute); value != 0 {
+		t.Errorf("expected no unmatched requests, got %g", value)
+	}
+
+}
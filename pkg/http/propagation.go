@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	RequestIDHeader = "X-Request-Id"
+)
+
+var (
+	DefaultPropagatedHeaders = []string{
+		RequestIDHeader,
+		"Traceparent",
+		"Tracestate",
+		"Baggage",
+		"X-B3-Traceid",
+		"X-B3-Spanid",
+		"X-B3-Parentspanid",
+		"X-B3-Sampled",
+		"B3",
+	}
+)
+
+type propagatedHeadersKey struct{}
+
+func NewRequestID() string {
+
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+
+}
+
+func ContextWithPropagatedHeaders(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, propagatedHeadersKey{}, header)
+}
+
+func PropagatedHeadersFromContext(ctx context.Context) http.Header {
+
+	header, ok := ctx.Value(propagatedHeadersKey{}).(http.Header)
+	if !ok {
+		return http.Header{}
+	}
+
+	return header
+
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	return PropagatedHeadersFromContext(ctx).Get(RequestIDHeader)
+}
+
+func ExtractPropagatedHeaders(header http.Header, names []string) http.Header {
+
+	extracted := make(http.Header)
+
+	for _, name := range names {
+		if values := header.Values(name); len(values) > 0 {
+			extracted[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+
+	return extracted
+
+}
+
+func InjectPropagatedHeaders(ctx context.Context, header http.Header) {
+	for name, values := range PropagatedHeadersFromContext(ctx) {
+		if len(header.Values(name)) == 0 {
+			header[name] = append([]string(nil), values...)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package listeners
+
+import "net"
+
+type ListenerService interface {
+	Listen(string, string) (net.Listener, error)
+	Ready() error
+}
@@ -0,0 +1,222 @@
+package restart
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+)
+
+var (
+	DefaultListenersEnv = "CONTAINER_KIT_LISTENERS"
+	DefaultReadyEnv     = "CONTAINER_KIT_READY_FD"
+)
+
+type ListenerAddress struct {
+	Network string `json:"network"`
+	Address string `json:"address"`
+}
+
+func (a ListenerAddress) String() string {
+	return a.Network + ":" + a.Address
+}
+
+type registeredListener struct {
+	address  ListenerAddress
+	listener net.Listener
+}
+
+type RegistryOption func(*Registry)
+
+type Registry struct {
+	mutex        sync.Mutex
+	listenersEnv string
+	readyEnv     string
+	listeners    []*registeredListener
+	inherited    map[ListenerAddress]*os.File
+	ready        *os.File
+	readyOnce    sync.Once
+}
+
+func WithListenersEnv(env string) RegistryOption {
+	return func(r *Registry) {
+		r.listenersEnv = env
+	}
+}
+
+func WithReadyEnv(env string) RegistryOption {
+	return func(r *Registry) {
+		r.readyEnv = env
+	}
+}
+
+func NewRegistry(options ...RegistryOption) *Registry {
+
+	r := &Registry{
+		listenersEnv: DefaultListenersEnv,
+		readyEnv:     DefaultReadyEnv,
+		inherited:    make(map[ListenerAddress]*os.File),
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	if err := r.inherit(); err != nil {
+		panic(err)
+	}
+
+	return r
+
+}
+
+func (r *Registry) inherit() error {
+
+	if value := os.Getenv(r.listenersEnv); len(value) > 0 {
+
+		var addresses []ListenerAddress
+
+		if err := json.Unmarshal([]byte(value), &addresses); err != nil {
+			return fmt.Errorf("invalid inherited listeners in %s: %w", r.listenersEnv, err)
+		}
+
+		for i, address := range addresses {
+			r.inherited[address] = os.NewFile(uintptr(3+i), address.String())
+		}
+
+		os.Unsetenv(r.listenersEnv)
+
+	}
+
+	if value := os.Getenv(r.readyEnv); len(value) > 0 {
+
+		fd, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid readiness descriptor in %s: %w", r.readyEnv, err)
+		}
+
+		r.ready = os.NewFile(uintptr(fd), "ready")
+
+		os.Unsetenv(r.readyEnv)
+
+	}
+
+	return nil
+
+}
+
+func (r *Registry) Inherited() bool {
+	return r.ready != nil
+}
+
+func (r *Registry) Listen(network string, address string) (net.Listener, error) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := ListenerAddress{Network: network, Address: address}
+
+	var listener net.Listener
+	var err error
+
+	if file, ok := r.inherited[key]; ok {
+
+		delete(r.inherited, key)
+
+		listener, err = net.FileListener(file)
+		file.Close()
+
+	} else {
+		listener, err = net.Listen(network, address)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if unixListener, ok := listener.(*net.UnixListener); ok {
+		unixListener.SetUnlinkOnClose(false)
+	}
+
+	r.listeners = append(r.listeners, &registeredListener{address: key, listener: listener})
+
+	return listener, nil
+
+}
+
+func (r *Registry) Ready() error {
+
+	var err error
+
+	r.readyOnce.Do(func() {
+
+		if r.ready == nil {
+			return
+		}
+
+		if _, err = r.ready.Write([]byte{1}); err != nil {
+			r.ready.Close()
+			return
+		}
+
+		err = r.ready.Close()
+
+	})
+
+	return err
+
+}
+
+func (r *Registry) Addresses() []ListenerAddress {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	addresses := make([]ListenerAddress, len(r.listeners))
+
+	for i, l := range r.listeners {
+		addresses[i] = l.address
+	}
+
+	return addresses
+
+}
+
+func (r *Registry) files() ([]ListenerAddress, []*os.File, error) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var addresses []ListenerAddress
+	var files []*os.File
+
+	for _, l := range r.listeners {
+
+		filer, ok := l.listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			closeFiles(files)
+			return nil, nil, fmt.Errorf("listener %s cannot be passed to a child process", l.address)
+		}
+
+		file, err := filer.File()
+		if err != nil {
+			closeFiles(files)
+			return nil, nil, err
+		}
+
+		addresses = append(addresses, l.address)
+		files = append(files, file)
+
+	}
+
+	return addresses, files, nil
+
+}
+
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		file.Close()
+	}
+}
@@ -0,0 +1,167 @@
+package restart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
+)
+
+var (
+	DefaultReadyTimeout = 30 * time.Second
+)
+
+var ErrRestartInProgress = errors.New("restart already in progress")
+
+type RestarterOption func(*Restarter)
+
+type Restarter struct {
+	registry     *Registry
+	executable   string
+	args         []string
+	readyTimeout time.Duration
+	logging      logging.LoggingService
+	restarting   atomic.Bool
+}
+
+func WithExecutable(executable string, args ...string) RestarterOption {
+	return func(r *Restarter) {
+		r.executable = executable
+		r.args = args
+	}
+}
+
+func WithReadyTimeout(timeout time.Duration) RestarterOption {
+	return func(r *Restarter) {
+		r.readyTimeout = timeout
+	}
+}
+
+func WithLogging(loggingService logging.LoggingService) RestarterOption {
+	return func(r *Restarter) {
+		r.logging = loggingService
+	}
+}
+
+func NewRestarter(registry *Registry, options ...RestarterOption) *Restarter {
+
+	r := &Restarter{
+		registry:     registry,
+		args:         os.Args[1:],
+		readyTimeout: DefaultReadyTimeout,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	if len(r.executable) == 0 {
+
+		executable, err := os.Executable()
+		if err != nil {
+			panic(err)
+		}
+
+		r.executable = executable
+
+	}
+
+	return r
+
+}
+
+func (r *Restarter) Restart() error {
+
+	if !r.restarting.CompareAndSwap(false, true) {
+		return ErrRestartInProgress
+	}
+
+	defer r.restarting.Store(false)
+
+	addresses, files, err := r.registry.files()
+	if err != nil {
+		return err
+	}
+
+	defer closeFiles(files)
+
+	encoded, err := json.Marshal(addresses)
+	if err != nil {
+		return err
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	defer readyReader.Close()
+
+	cmd := exec.Command(r.executable, r.args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, readyWriter)
+	cmd.Env = append(os.Environ(),
+		r.registry.listenersEnv+"="+string(encoded),
+		r.registry.readyEnv+"="+strconv.Itoa(3+len(files)),
+	)
+
+	if err := cmd.Start(); err != nil {
+		readyWriter.Close()
+		return err
+	}
+
+	readyWriter.Close()
+
+	if r.logging != nil {
+		r.logging.Info("started replacement process", "pid", cmd.Process.Pid, "listeners", len(files))
+	}
+
+	if err := r.waitForReady(cmd, readyReader); err != nil {
+		cmd.Process.Kill()
+		go cmd.Wait()
+		return err
+	}
+
+	if r.logging != nil {
+		r.logging.Info("replacement process is ready", "pid", cmd.Process.Pid)
+	}
+
+	go cmd.Process.Release()
+
+	return nil
+
+}
+
+func (r *Restarter) waitForReady(cmd *exec.Cmd, readyReader *os.File) error {
+
+	ready := make(chan error, 1)
+
+	go func() {
+
+		buf := make([]byte, 1)
+
+		if _, err := readyReader.Read(buf); err != nil {
+			ready <- fmt.Errorf("replacement process %d exited before becoming ready: %w", cmd.Process.Pid, err)
+			return
+		}
+
+		ready <- nil
+
+	}()
+
+	select {
+	case err := <-ready:
+		return err
+	case <-time.After(r.readyTimeout):
+		return fmt.Errorf("replacement process %d did not become ready within %s", cmd.Process.Pid, r.readyTimeout)
+	}
+
+}
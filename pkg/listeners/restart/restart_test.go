@@ -0,0 +1,144 @@
+package restart
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+const (
+	childModeEnv = "RESTART_TEST_CHILD_MODE"
+	childAddress = "127.0.0.1:0"
+)
+
+func TestMain(m *testing.M) {
+
+	switch os.Getenv(childModeEnv) {
+	case "serve":
+		serveChild()
+	case "fail":
+		os.Exit(1)
+	}
+
+	os.Exit(m.Run())
+
+}
+
+func serveChild() {
+
+	registry := NewRegistry()
+
+	listener, err := registry.Listen("tcp", childAddress)
+	if err != nil {
+		os.Exit(2)
+	}
+
+	exit := make(chan struct{})
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "child inherited=%t", registry.Inherited())
+	})
+
+	mux.HandleFunc("/exit", func(w http.ResponseWriter, r *http.Request) {
+		close(exit)
+	})
+
+	go http.Serve(listener, mux)
+
+	if err := registry.Ready(); err != nil {
+		os.Exit(3)
+	}
+
+	select {
+	case <-exit:
+	case <-time.After(10 * time.Second):
+	}
+
+	os.Exit(0)
+
+}
+
+func get(t *testing.T, url string) string {
+
+	t.Helper()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("request to %s failed: %v", url, err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading response from %s failed: %v", url, err)
+	}
+
+	return string(body)
+
+}
+
+func TestRestartPassesListenersToChild(t *testing.T) {
+
+	registry := NewRegistry()
+
+	listener, err := registry.Listen("tcp", childAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url := "http://" + listener.Addr().String()
+
+	t.Setenv(childModeEnv, "serve")
+
+	restarter := NewRestarter(registry,
+		WithExecutable(os.Args[0], "-test.run=^$"),
+		WithReadyTimeout(10*time.Second),
+	)
+
+	if err := restarter.Restart(); err != nil {
+		t.Fatalf("restart failed: %v", err)
+	}
+
+	listener.Close()
+
+	if body := get(t, url+"/"); body != "child inherited=true" {
+		t.Errorf("expected the child to serve on the inherited listener, got %q", body)
+	}
+
+	if resp, err := http.Get(url + "/exit"); err == nil {
+		resp.Body.Close()
+	}
+
+}
+
+func TestRestartFailsWhenChildExitsBeforeReady(t *testing.T) {
+
+	registry := NewRegistry()
+
+	listener, err := registry.Listen("tcp", childAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	t.Setenv(childModeEnv, "fail")
+
+	restarter := NewRestarter(registry,
+		WithExecutable(os.Args[0], "-test.run=^$"),
+		WithReadyTimeout(10*time.Second),
+	)
+
+	if err := restarter.Restart(); err == nil {
+		t.Fatal("expected restart to fail when the child exits before becoming ready")
+	}
+
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	CounterType   = "counter"
+	GaugeType     = "gauge"
+	HistogramType = "histogram"
+)
+
+type Cataloger interface {
+	Catalog() *Catalog
+}
+
+type MetricDescription struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Help        string            `json:"help,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	ConstLabels map[string]string `json:"constLabels,omitempty"`
+	Buckets     []float64         `json:"buckets,omitempty"`
+}
+
+type Catalog struct {
+	mutex   sync.RWMutex
+	metrics map[string]MetricDescription
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{
+		metrics: make(map[string]MetricDescription),
+	}
+}
+
+func (c *Catalog) Add(description MetricDescription) {
+
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.metrics[description.Name] = description
+
+}
+
+func (c *Catalog) Metrics() []MetricDescription {
+
+	if c == nil {
+		return []MetricDescription{}
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	descriptions := make([]MetricDescription, 0, len(c.metrics))
+
+	for _, description := range c.metrics {
+		descriptions = append(descriptions, description)
+	}
+
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Name < descriptions[j].Name
+	})
+
+	return descriptions
+
+}
+
+func (c *Catalog) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(c.Metrics()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+	})
+}
+
+func (c *Catalog) WriteMarkdown(w io.Writer) error {
+	return WriteCatalogMarkdown(w, c.Metrics())
+}
+
+func ReadCatalog(r io.Reader) ([]MetricDescription, error) {
+
+	var descriptions []MetricDescription
+
+	if err := json.NewDecoder(r).Decode(&descriptions); err != nil {
+		return nil, err
+	}
+
+	return descriptions, nil
+
+}
+
+func WriteCatalogMarkdown(w io.Writer, descriptions []MetricDescription) error {
+
+	var b strings.Builder
+
+	b.WriteString("| Name | Type | Labels | Buckets | Description |\n")
+	b.WriteString("| ---- | ---- | ------ | ------- | ----------- |\n")
+
+	for _, description := range descriptions {
+
+		labels := append([]string(nil), description.Labels...)
+
+		for key, value := range description.ConstLabels {
+			labels = append(labels, fmt.Sprintf("%s=%q", key, value))
+		}
+
+		sort.Strings(labels[len(description.Labels):])
+
+		buckets := make([]string, len(description.Buckets))
+
+		for i, bucket := range description.Buckets {
+			buckets[i] = fmt.Sprintf("%g", bucket)
+		}
+
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n",
+			description.Name,
+			description.Type,
+			markdownCell(strings.Join(labels, ", ")),
+			markdownCell(strings.Join(buckets, ", ")),
+			markdownCell(description.Help),
+		)
+
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+
+}
+
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(text)
+}
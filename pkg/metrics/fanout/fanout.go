@@ -0,0 +1,349 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+type MetricServiceOption func(*MetricService)
+
+type MetricService struct {
+	primary  metrics.MetricService
+	backends []metrics.MetricService
+}
+
+func WithPrimary(primary metrics.MetricService) MetricServiceOption {
+	return func(m *MetricService) {
+		m.primary = primary
+		m.add(primary)
+	}
+}
+
+func WithBackends(backends ...metrics.MetricService) MetricServiceOption {
+	return func(m *MetricService) {
+		m.add(backends...)
+	}
+}
+
+func NewMetricService(options ...MetricServiceOption) *MetricService {
+
+	m := &MetricService{}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	if m.primary == nil && len(m.backends) > 0 {
+		m.primary = m.backends[0]
+	}
+
+	return m
+
+}
+
+func (m *MetricService) add(backends ...metrics.MetricService) {
+
+	for _, backend := range backends {
+
+		if m.contains(backend) {
+			continue
+		}
+
+		m.backends = append(m.backends, backend)
+
+	}
+
+}
+
+func (m *MetricService) contains(backend metrics.MetricService) bool {
+
+	for _, existing := range m.backends {
+		if existing == backend {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func (m *MetricService) Counter(options ...interface{}) metrics.Counter {
+
+	counters := make(Counter, len(m.backends))
+
+	for i, backend := range m.backends {
+		counters[i] = backend.Counter(options...)
+	}
+
+	return counters
+
+}
+
+func (m *MetricService) Gauge(options ...interface{}) metrics.Gauge {
+
+	gauges := make(Gauge, len(m.backends))
+
+	for i, backend := range m.backends {
+		gauges[i] = backend.Gauge(options...)
+	}
+
+	return gauges
+
+}
+
+func (m *MetricService) Histogram(options ...interface{}) metrics.Histogram {
+
+	histograms := make(Histogram, len(m.backends))
+
+	for i, backend := range m.backends {
+		histograms[i] = backend.Histogram(options...)
+	}
+
+	return histograms
+
+}
+
+func (m *MetricService) GaugeFunc(function func() float64, options ...interface{}) {
+	for _, backend := range m.backends {
+		backend.GaugeFunc(function, options...)
+	}
+}
+
+func (m *MetricService) CounterFunc(function func() float64, options ...interface{}) {
+	for _, backend := range m.backends {
+		backend.CounterFunc(function, options...)
+	}
+}
+
+func (m *MetricService) Handler() http.Handler {
+
+	if m.primary == nil {
+		return http.NotFoundHandler()
+	}
+
+	return m.primary.Handler()
+
+}
+
+func (m *MetricService) Push() error {
+
+	var errs []error
+
+	for _, backend := range m.backends {
+		if err := backend.Push(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+
+}
+
+func (m *MetricService) Close() error {
+
+	var errs []error
+
+	for _, backend := range m.backends {
+		if closer, ok := backend.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+
+}
+
+func (m *MetricService) Catalog() *metrics.Catalog {
+
+	if cataloger, ok := m.primary.(metrics.Cataloger); ok {
+		return cataloger.Catalog()
+	}
+
+	return nil
+
+}
+
+func (m *MetricService) Backends() []metrics.MetricService {
+	return m.backends
+}
+
+type Counter []metrics.Counter
+
+func (c Counter) WithLabels(keysAndValues ...string) metrics.Counter {
+
+	counters := make(Counter, len(c))
+
+	for i, counter := range c {
+		counters[i] = counter.WithLabels(keysAndValues...)
+	}
+
+	return counters
+
+}
+
+func (c Counter) WithLabelValues(values ...string) metrics.Counter {
+
+	counters := make(Counter, len(c))
+
+	for i, counter := range c {
+		counters[i] = counter.WithLabelValues(values...)
+	}
+
+	return counters
+
+}
+
+func (c Counter) Add(value float64) {
+	for _, counter := range c {
+		counter.Add(value)
+	}
+}
+
+func (c Counter) Inc() {
+	for _, counter := range c {
+		counter.Inc()
+	}
+}
+
+func (c Counter) AddWithExemplar(value float64, exemplar map[string]string) {
+	for _, counter := range c {
+		counter.AddWithExemplar(value, exemplar)
+	}
+}
+
+func (c Counter) AddWithContext(ctx context.Context, value float64) {
+	for _, counter := range c {
+		counter.AddWithContext(ctx, value)
+	}
+}
+
+type Gauge []metrics.Gauge
+
+func (g Gauge) WithLabels(keysAndValues ...string) metrics.Gauge {
+
+	gauges := make(Gauge, len(g))
+
+	for i, gauge := range g {
+		gauges[i] = gauge.WithLabels(keysAndValues...)
+	}
+
+	return gauges
+
+}
+
+func (g Gauge) WithLabelValues(values ...string) metrics.Gauge {
+
+	gauges := make(Gauge, len(g))
+
+	for i, gauge := range g {
+		gauges[i] = gauge.WithLabelValues(values...)
+	}
+
+	return gauges
+
+}
+
+func (g Gauge) Add(value float64) {
+	for _, gauge := range g {
+		gauge.Add(value)
+	}
+}
+
+func (g Gauge) Sub(value float64) {
+	for _, gauge := range g {
+		gauge.Sub(value)
+	}
+}
+
+func (g Gauge) Set(value float64) {
+	for _, gauge := range g {
+		gauge.Set(value)
+	}
+}
+
+func (g Gauge) Inc() {
+	for _, gauge := range g {
+		gauge.Inc()
+	}
+}
+
+func (g Gauge) Dec() {
+	for _, gauge := range g {
+		gauge.Dec()
+	}
+}
+
+func (g Gauge) SetToCurrentTime() {
+	for _, gauge := range g {
+		gauge.SetToCurrentTime()
+	}
+}
+
+type Histogram []metrics.Histogram
+
+func (h Histogram) WithLabels(keysAndValues ...string) metrics.Histogram {
+
+	histograms := make(Histogram, len(h))
+
+	for i, histogram := range h {
+		histograms[i] = histogram.WithLabels(keysAndValues...)
+	}
+
+	return histograms
+
+}
+
+func (h Histogram) WithLabelValues(values ...string) metrics.Histogram {
+
+	histograms := make(Histogram, len(h))
+
+	for i, histogram := range h {
+		histograms[i] = histogram.WithLabelValues(values...)
+	}
+
+	return histograms
+
+}
+
+func (h Histogram) Observe(value float64) {
+	for _, histogram := range h {
+		histogram.Observe(value)
+	}
+}
+
+func (h Histogram) ObserveWithExemplar(value float64, exemplar map[string]string) {
+	for _, histogram := range h {
+		histogram.ObserveWithExemplar(value, exemplar)
+	}
+}
+
+func (h Histogram) ObserveWithContext(ctx context.Context, value float64) {
+	for _, histogram := range h {
+		histogram.ObserveWithContext(ctx, value)
+	}
+}
+
+func (h Histogram) ObserveDuration(duration time.Duration) {
+	for _, histogram := range h {
+		histogram.ObserveDuration(duration)
+	}
+}
+
+func (h Histogram) ObserveSince(start time.Time) {
+	h.ObserveDuration(time.Since(start))
+}
+
+func (h Histogram) StartTimer() *metrics.Timer {
+	return metrics.NewTimer(h)
+}
+
+func (h Histogram) StartTimerWithContext(ctx context.Context) *metrics.Timer {
+	return metrics.NewTimerWithContext(ctx, h)
+}
@@ -0,0 +1,54 @@
+package fanout
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/metrictest"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+)
+
+func TestPrimaryListedAsBackendIsRegisteredOnce(t *testing.T) {
+
+	primary := prometheus.NewMetricService()
+	secondary := metrictest.NewMetricService()
+
+	m := NewMetricService(
+		WithPrimary(primary),
+		WithBackends(primary, secondary),
+	)
+
+	if backends := m.Backends(); len(backends) != 2 {
+		t.Fatalf("expected 2 backends, got %d", len(backends))
+	}
+
+	m.Counter(metrics.WithName("jobs_total"), metrics.WithHelp("Jobs.")).Add(2)
+
+	if value := secondary.CounterValue("jobs_total"); value != 2 {
+		t.Errorf("expected secondary to receive 2, got %g", value)
+	}
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if !strings.Contains(recorder.Body.String(), "jobs_total 2") {
+		t.Errorf("expected primary to expose jobs_total, got %s", recorder.Body.String())
+	}
+
+}
+
+func TestCatalogWithoutPrimary(t *testing.T) {
+
+	m := NewMetricService()
+
+	recorder := httptest.NewRecorder()
+	m.Catalog().Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/catalog", nil))
+
+	if body := strings.TrimSpace(recorder.Body.String()); recorder.Code != http.StatusOK || body != "[]" {
+		t.Errorf("expected an empty catalog, got %d %q", recorder.Code, body)
+	}
+
+}
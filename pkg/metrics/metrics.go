@@ -1,11 +1,39 @@
 package metrics
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	exemplarFunc atomic.Value
+)
+
+type ExemplarFunc func(context.Context) map[string]string
+
+func SetExemplarFunc(function ExemplarFunc) {
+	exemplarFunc.Store(function)
+}
+
+func ExemplarFromContext(ctx context.Context) map[string]string {
+
+	function, _ := exemplarFunc.Load().(ExemplarFunc)
+	if function == nil {
+		return nil
+	}
+
+	return function(ctx)
+
+}
 
 type MetricService interface {
 	Counter(...interface{}) Counter
 	Gauge(...interface{}) Gauge
 	Histogram(...interface{}) Histogram
+	GaugeFunc(func() float64, ...interface{})
+	CounterFunc(func() float64, ...interface{})
 	Handler() http.Handler
 	Push() error
 }
@@ -13,19 +41,100 @@ type MetricService interface {
 type Counter interface {
 	WithLabels(...string) Counter
 	WithLabelValues(...string) Counter
+	Inc()
 	Add(float64)
+	AddWithExemplar(float64, map[string]string)
+	AddWithContext(context.Context, float64)
 }
 
 type Gauge interface {
 	WithLabels(...string) Gauge
 	WithLabelValues(...string) Gauge
+	Inc()
+	Dec()
 	Add(float64)
 	Sub(float64)
 	Set(float64)
+	SetToCurrentTime()
 }
 
 type Histogram interface {
 	WithLabels(...string) Histogram
 	WithLabelValues(...string) Histogram
 	Observe(float64)
+	ObserveWithExemplar(float64, map[string]string)
+	ObserveWithContext(context.Context, float64)
+	ObserveDuration(time.Duration)
+	ObserveSince(time.Time)
+	StartTimer() *Timer
+	StartTimerWithContext(context.Context) *Timer
+}
+
+type MetricOption func(*MetricOptionSet)
+
+type MetricOptionSet struct {
+	Namespace   string
+	Subsystem   string
+	Name        string
+	Help        string
+	Buckets     []float64
+	Labels      []string
+	ConstLabels map[string]string
+}
+
+func WithNamespace(namespace string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.Namespace = namespace
+	}
+}
+
+func WithSubsystem(subsystem string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.Subsystem = subsystem
+	}
+}
+
+func WithName(name string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.Name = name
+	}
+}
+
+func WithHelp(help string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.Help = help
+	}
+}
+
+func WithBuckets(buckets []float64) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.Buckets = buckets
+	}
+}
+
+func WithLabels(labels []string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.Labels = labels
+	}
+}
+
+func WithConstLabel(key, value string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		if optionSet.ConstLabels == nil {
+			optionSet.ConstLabels = make(map[string]string)
+		}
+		optionSet.ConstLabels[key] = value
+	}
+}
+
+func NewMetricOptionSet(options ...MetricOption) *MetricOptionSet {
+
+	o := &MetricOptionSet{}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	return o
+
 }
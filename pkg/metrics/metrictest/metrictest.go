@@ -0,0 +1,610 @@
+package metrictest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+	client "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	AddOperation     = "add"
+	SubOperation     = "sub"
+	SetOperation     = "set"
+	ObserveOperation = "observe"
+)
+
+func InterfaceSliceToMetricOptionSet(interfaceOptions []interface{}) *metrics.MetricOptionSet {
+
+	options := make([]prometheus.MetricOption, len(interfaceOptions))
+
+	for i, interfaceOption := range interfaceOptions {
+		switch option := interfaceOption.(type) {
+		case metrics.MetricOption:
+			options[i] = prometheus.FromMetricOption(option)
+		default:
+			options[i] = interfaceOption.(prometheus.MetricOption)
+		}
+	}
+
+	o := prometheus.NewMetricOptionSet(options...)
+
+	return &metrics.MetricOptionSet{
+		Namespace:   o.Namespace,
+		Subsystem:   o.Subsystem,
+		Name:        o.Name,
+		Help:        o.Help,
+		Buckets:     o.Buckets,
+		Labels:      o.Labels,
+		ConstLabels: o.ConstLabels,
+	}
+
+}
+
+func StringSliceToLabels(keysAndValues []string) map[string]string {
+
+	labels := make(map[string]string)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		labels[keysAndValues[i]] = keysAndValues[i+1]
+	}
+
+	return labels
+
+}
+
+func LabelValuesToLabels(labels []string, values []string) map[string]string {
+
+	result := make(map[string]string, len(labels))
+
+	for i := 0; i < len(labels) && i < len(values); i++ {
+		result[labels[i]] = values[i]
+	}
+
+	return result
+
+}
+
+func LabelsKey(labels map[string]string) string {
+
+	pairs := make([]string, 0, len(labels))
+
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+
+}
+
+func MatchLabels(labels map[string]string, keysAndValues []string) bool {
+
+	for key, value := range StringSliceToLabels(keysAndValues) {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+type Record struct {
+	Name      string
+	Labels    map[string]string
+	Operation string
+	Value     float64
+	Exemplar  map[string]string
+}
+
+type Series struct {
+	Labels       map[string]string
+	Value        float64
+	Observations []float64
+	Exemplar     map[string]string
+}
+
+type Metric struct {
+	Name        string
+	Help        string
+	Type        string
+	Labels      []string
+	ConstLabels map[string]string
+	Buckets     []float64
+	function    func() float64
+	series      map[string]*Series
+}
+
+type MetricService struct {
+	mutex   sync.Mutex
+	metrics map[string]*Metric
+	records []Record
+}
+
+func NewMetricService() *MetricService {
+	return &MetricService{
+		metrics: make(map[string]*Metric),
+	}
+}
+
+func (m *MetricService) register(metricType string, options []interface{}) *Metric {
+
+	o := InterfaceSliceToMetricOptionSet(options)
+	name := client.BuildFQName(o.Namespace, o.Subsystem, o.Name)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.metrics[name]; ok {
+		panic(fmt.Sprintf("metrictest: duplicate metrics collector registration attempted for %q", name))
+	}
+
+	metric := &Metric{
+		Name:        name,
+		Help:        o.Help,
+		Type:        metricType,
+		Labels:      o.Labels,
+		ConstLabels: o.ConstLabels,
+		Buckets:     o.Buckets,
+		series:      make(map[string]*Series),
+	}
+
+	m.metrics[name] = metric
+
+	return metric
+
+}
+
+func (metric *Metric) labelsFromValues(values []string) map[string]string {
+
+	if len(values) != len(metric.Labels) {
+		panic(fmt.Sprintf("metrictest: inconsistent label cardinality for %q: expected %d label values but got %d in %#v", metric.Name, len(metric.Labels), len(values), values))
+	}
+
+	return LabelValuesToLabels(metric.Labels, values)
+
+}
+
+func (metric *Metric) labelsFromPairs(keysAndValues []string) map[string]string {
+
+	labels := StringSliceToLabels(keysAndValues)
+
+	if len(labels) != len(metric.Labels) {
+		panic(fmt.Sprintf("metrictest: inconsistent label cardinality for %q: expected %d label values but got %d in %#v", metric.Name, len(metric.Labels), len(labels), labels))
+	}
+
+	for _, label := range metric.Labels {
+		if _, ok := labels[label]; !ok {
+			panic(fmt.Sprintf("metrictest: label name %q missing in label map for %q", label, metric.Name))
+		}
+	}
+
+	return labels
+
+}
+
+func (m *MetricService) record(metric *Metric, labels map[string]string, operation string, value float64, exemplar map[string]string) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	all := make(map[string]string, len(metric.ConstLabels)+len(labels))
+
+	for key, value := range metric.ConstLabels {
+		all[key] = value
+	}
+
+	for key, value := range labels {
+		all[key] = value
+	}
+
+	key := LabelsKey(all)
+
+	series, ok := metric.series[key]
+	if !ok {
+		series = &Series{Labels: all}
+		metric.series[key] = series
+	}
+
+	switch operation {
+	case AddOperation:
+		series.Value += value
+	case SubOperation:
+		series.Value -= value
+	case SetOperation:
+		series.Value = value
+	case ObserveOperation:
+		series.Observations = append(series.Observations, value)
+	}
+
+	if len(exemplar) > 0 {
+		series.Exemplar = exemplar
+	}
+
+	m.records = append(m.records, Record{
+		Name:      metric.Name,
+		Labels:    all,
+		Operation: operation,
+		Value:     value,
+		Exemplar:  exemplar,
+	})
+
+}
+
+func (m *MetricService) Counter(options ...interface{}) metrics.Counter {
+	return &Counter{
+		service: m,
+		metric:  m.register(metrics.CounterType, options),
+	}
+}
+
+func (m *MetricService) Gauge(options ...interface{}) metrics.Gauge {
+	return &Gauge{
+		service: m,
+		metric:  m.register(metrics.GaugeType, options),
+	}
+}
+
+func (m *MetricService) Histogram(options ...interface{}) metrics.Histogram {
+	return &Histogram{
+		service: m,
+		metric:  m.register(metrics.HistogramType, options),
+	}
+}
+
+func (m *MetricService) GaugeFunc(function func() float64, options ...interface{}) {
+	m.register(metrics.GaugeType, options).function = function
+}
+
+func (m *MetricService) CounterFunc(function func() float64, options ...interface{}) {
+	m.register(metrics.CounterType, options).function = function
+}
+
+func (m *MetricService) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		m.mutex.Lock()
+		names := m.names()
+		types := make(map[string]string, len(names))
+		for _, name := range names {
+			types[name] = m.metrics[name].Type
+		}
+		m.mutex.Unlock()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+		for _, name := range names {
+			for _, series := range m.Series(name) {
+
+				key := LabelsKey(series.Labels)
+
+				if types[name] == metrics.HistogramType {
+					fmt.Fprintf(w, "%s{%s} count=%d\n", name, key, len(series.Observations))
+					continue
+				}
+
+				fmt.Fprintf(w, "%s{%s} %g\n", name, key, series.Value)
+
+			}
+		}
+
+	})
+}
+
+func (m *MetricService) Push() error {
+	return nil
+}
+
+func (m *MetricService) names() []string {
+
+	names := make([]string, 0, len(m.metrics))
+
+	for name := range m.metrics {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+
+}
+
+func (m *MetricService) seriesKeys(metric *Metric) []string {
+
+	keys := make([]string, 0, len(metric.series))
+
+	for key := range metric.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+
+}
+
+func (m *MetricService) matching(name string, keysAndValues []string) []Series {
+
+	m.mutex.Lock()
+
+	metric, ok := m.metrics[name]
+	if !ok {
+		m.mutex.Unlock()
+		return nil
+	}
+
+	if metric.function != nil {
+
+		function := metric.function
+		labels := metric.ConstLabels
+
+		m.mutex.Unlock()
+
+		if !MatchLabels(labels, keysAndValues) {
+			return nil
+		}
+
+		return []Series{{Labels: labels, Value: function()}}
+
+	}
+
+	defer m.mutex.Unlock()
+
+	var matched []Series
+
+	for _, key := range m.seriesKeys(metric) {
+		if series := metric.series[key]; MatchLabels(series.Labels, keysAndValues) {
+			matched = append(matched, Series{
+				Labels:       series.Labels,
+				Value:        series.Value,
+				Observations: append([]float64(nil), series.Observations...),
+				Exemplar:     series.Exemplar,
+			})
+		}
+	}
+
+	return matched
+
+}
+
+func (m *MetricService) Registered(name string) bool {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, ok := m.metrics[name]
+
+	return ok
+
+}
+
+func (m *MetricService) Metric(name string) (Metric, bool) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	metric, ok := m.metrics[name]
+	if !ok {
+		return Metric{}, false
+	}
+
+	return *metric, true
+
+}
+
+func (m *MetricService) Value(name string, keysAndValues ...string) float64 {
+
+	var value float64
+
+	for _, series := range m.matching(name, keysAndValues) {
+		value += series.Value
+	}
+
+	return value
+
+}
+
+func (m *MetricService) CounterValue(name string, keysAndValues ...string) float64 {
+	return m.Value(name, keysAndValues...)
+}
+
+func (m *MetricService) GaugeValue(name string, keysAndValues ...string) float64 {
+	return m.Value(name, keysAndValues...)
+}
+
+func (m *MetricService) Observations(name string, keysAndValues ...string) []float64 {
+
+	var observations []float64
+
+	for _, series := range m.matching(name, keysAndValues) {
+		observations = append(observations, series.Observations...)
+	}
+
+	return observations
+
+}
+
+func (m *MetricService) ObservationCount(name string, keysAndValues ...string) int {
+	return len(m.Observations(name, keysAndValues...))
+}
+
+func (m *MetricService) ObservationSum(name string, keysAndValues ...string) float64 {
+
+	var sum float64
+
+	for _, observation := range m.Observations(name, keysAndValues...) {
+		sum += observation
+	}
+
+	return sum
+
+}
+
+func (m *MetricService) Series(name string) []Series {
+	return m.matching(name, nil)
+}
+
+func (m *MetricService) Records() []Record {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]Record(nil), m.records...)
+
+}
+
+func (m *MetricService) Reset() {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, metric := range m.metrics {
+		metric.series = make(map[string]*Series)
+	}
+
+	m.records = nil
+
+}
+
+type Counter struct {
+	service *MetricService
+	metric  *Metric
+	labels  map[string]string
+}
+
+func (c *Counter) WithLabels(keysAndValues ...string) metrics.Counter {
+	return &Counter{
+		service: c.service,
+		metric:  c.metric,
+		labels:  c.metric.labelsFromPairs(keysAndValues),
+	}
+}
+
+func (c *Counter) WithLabelValues(values ...string) metrics.Counter {
+	return &Counter{
+		service: c.service,
+		metric:  c.metric,
+		labels:  c.metric.labelsFromValues(values),
+	}
+}
+
+func (c *Counter) Add(value float64) {
+	c.service.record(c.metric, c.labels, AddOperation, value, nil)
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) AddWithExemplar(value float64, exemplar map[string]string) {
+	c.service.record(c.metric, c.labels, AddOperation, value, exemplar)
+}
+
+func (c *Counter) AddWithContext(ctx context.Context, value float64) {
+	c.AddWithExemplar(value, metrics.ExemplarFromContext(ctx))
+}
+
+type Gauge struct {
+	service *MetricService
+	metric  *Metric
+	labels  map[string]string
+}
+
+func (g *Gauge) WithLabels(keysAndValues ...string) metrics.Gauge {
+	return &Gauge{
+		service: g.service,
+		metric:  g.metric,
+		labels:  g.metric.labelsFromPairs(keysAndValues),
+	}
+}
+
+func (g *Gauge) WithLabelValues(values ...string) metrics.Gauge {
+	return &Gauge{
+		service: g.service,
+		metric:  g.metric,
+		labels:  g.metric.labelsFromValues(values),
+	}
+}
+
+func (g *Gauge) Add(value float64) {
+	g.service.record(g.metric, g.labels, AddOperation, value, nil)
+}
+
+func (g *Gauge) Sub(value float64) {
+	g.service.record(g.metric, g.labels, SubOperation, value, nil)
+}
+
+func (g *Gauge) Set(value float64) {
+	g.service.record(g.metric, g.labels, SetOperation, value, nil)
+}
+
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.Sub(1)
+}
+
+func (g *Gauge) SetToCurrentTime() {
+	g.Set(float64(time.Now().UnixNano()) / 1e9)
+}
+
+type Histogram struct {
+	service *MetricService
+	metric  *Metric
+	labels  map[string]string
+}
+
+func (h *Histogram) WithLabels(keysAndValues ...string) metrics.Histogram {
+	return &Histogram{
+		service: h.service,
+		metric:  h.metric,
+		labels:  h.metric.labelsFromPairs(keysAndValues),
+	}
+}
+
+func (h *Histogram) WithLabelValues(values ...string) metrics.Histogram {
+	return &Histogram{
+		service: h.service,
+		metric:  h.metric,
+		labels:  h.metric.labelsFromValues(values),
+	}
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.service.record(h.metric, h.labels, ObserveOperation, value, nil)
+}
+
+func (h *Histogram) ObserveWithExemplar(value float64, exemplar map[string]string) {
+	h.service.record(h.metric, h.labels, ObserveOperation, value, exemplar)
+}
+
+func (h *Histogram) ObserveWithContext(ctx context.Context, value float64) {
+	h.ObserveWithExemplar(value, metrics.ExemplarFromContext(ctx))
+}
+
+func (h *Histogram) ObserveDuration(duration time.Duration) {
+	h.Observe(duration.Seconds())
+}
+
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (h *Histogram) StartTimer() *metrics.Timer {
+	return metrics.NewTimer(h)
+}
+
+func (h *Histogram) StartTimerWithContext(ctx context.Context) *metrics.Timer {
+	return metrics.NewTimerWithContext(ctx, h)
+}
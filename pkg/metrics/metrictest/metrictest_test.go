@@ -0,0 +1,106 @@
+package metrictest
+
+import (
+	"testing"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+)
+
+var backends = map[string]func() metrics.MetricService{
+	"metrictest": func() metrics.MetricService {
+		return NewMetricService()
+	},
+	"prometheus": func() metrics.MetricService {
+		return prometheus.NewMetricService()
+	},
+}
+
+func assertPanics(t *testing.T, name string, f func()) {
+
+	t.Helper()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s: expected a panic", name)
+		}
+	}()
+
+	f()
+
+}
+
+func TestDuplicateRegistrationPanicsLikePrometheus(t *testing.T) {
+
+	for name, newBackend := range backends {
+
+		m := newBackend()
+
+		m.Counter(metrics.WithName("jobs_total"))
+
+		assertPanics(t, name, func() {
+			m.Counter(metrics.WithName("jobs_total"))
+		})
+
+		assertPanics(t, name, func() {
+			m.Gauge(metrics.WithName("jobs_total"))
+		})
+
+	}
+
+}
+
+func TestInconsistentLabelsPanicLikePrometheus(t *testing.T) {
+
+	for name, newBackend := range backends {
+
+		counter := newBackend().Counter(
+			metrics.WithName("requests_total"),
+			metrics.WithLabels([]string{"method", "status"}),
+		)
+
+		counter.WithLabelValues("GET", "200").Inc()
+		counter.WithLabels("method", "GET", "status", "200").Inc()
+
+		assertPanics(t, name, func() {
+			counter.WithLabelValues("GET")
+		})
+
+		assertPanics(t, name, func() {
+			counter.WithLabelValues("GET", "200", "extra")
+		})
+
+		assertPanics(t, name, func() {
+			counter.WithLabels("method", "GET", "code", "200")
+		})
+
+	}
+
+}
+
+func TestRecordsValuesByLabels(t *testing.T) {
+
+	m := NewMetricService()
+
+	counter := m.Counter(
+		metrics.WithName("requests_total"),
+		metrics.WithLabels([]string{"method"}),
+	)
+
+	counter.WithLabelValues("GET").Add(2)
+	counter.WithLabels("method", "POST").Inc()
+
+	if value := m.CounterValue("requests_total", "method", "GET"); value != 2 {
+		t.Errorf("expected 2 GET requests, got %g", value)
+	}
+
+	if value := m.CounterValue("requests_total"); value != 3 {
+		t.Errorf("expected 3 requests in total, got %g", value)
+	}
+
+	metric, ok := m.Metric("requests_total")
+	if !ok || metric.Type != metrics.CounterType {
+		t.Errorf("expected a registered counter, got %+v", metric)
+	}
+
+}
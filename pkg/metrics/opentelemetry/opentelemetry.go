@@ -0,0 +1,545 @@
+package opentelemetry
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	DefaultMeterName = "github.com/definancialbr/golang-container-kit"
+)
+
+var (
+	DefaultExportInterval = 15 * time.Second
+	DefaultPushTimeout    = 10 * time.Second
+)
+
+func InterfaceSliceToMetricOptionSlice(interfaceOptions []interface{}) []metrics.MetricOption {
+
+	options := make([]metrics.MetricOption, len(interfaceOptions))
+
+	for i, interfaceOption := range interfaceOptions {
+		switch option := interfaceOption.(type) {
+		case prometheus.MetricOption:
+			options[i] = prometheus.ToMetricOption(option)
+		default:
+			options[i] = interfaceOption.(metrics.MetricOption)
+		}
+	}
+
+	return options
+
+}
+
+func StringSliceToAttributes(keysAndValues []string) []attribute.KeyValue {
+
+	attributes := make([]attribute.KeyValue, 0, len(keysAndValues)/2)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		attributes = append(attributes, attribute.String(keysAndValues[i], keysAndValues[i+1]))
+	}
+
+	return attributes
+
+}
+
+func LabelValuesToAttributes(labels []string, values []string) []attribute.KeyValue {
+
+	attributes := make([]attribute.KeyValue, 0, len(labels))
+
+	for i := 0; i < len(labels) && i < len(values); i++ {
+		attributes = append(attributes, attribute.String(labels[i], values[i]))
+	}
+
+	return attributes
+
+}
+
+func MapToAttributes(labels map[string]string) []attribute.KeyValue {
+
+	attributes := make([]attribute.KeyValue, 0, len(labels))
+
+	for key, value := range labels {
+		attributes = append(attributes, attribute.String(key, value))
+	}
+
+	return attributes
+
+}
+
+func FullyQualifiedName(o *metrics.MetricOptionSet) string {
+
+	parts := make([]string, 0, 3)
+
+	for _, part := range []string{o.Namespace, o.Subsystem, o.Name} {
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "_")
+
+}
+
+type MetricServiceOption func(*MetricService)
+
+type MetricService struct {
+	provider           *sdkmetric.MeterProvider
+	meter              metric.Meter
+	meterName          string
+	reader             sdkmetric.Reader
+	exporterOptions    []otlpmetrichttp.Option
+	exportInterval     time.Duration
+	pushTimeout        time.Duration
+	resourceAttributes []attribute.KeyValue
+}
+
+func WithEndpoint(endpoint string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.exporterOptions = append(m.exporterOptions, otlpmetrichttp.WithEndpoint(endpoint))
+	}
+}
+
+func WithEndpointURL(url string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.exporterOptions = append(m.exporterOptions, otlpmetrichttp.WithEndpointURL(url))
+	}
+}
+
+func WithURLPath(urlPath string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.exporterOptions = append(m.exporterOptions, otlpmetrichttp.WithURLPath(urlPath))
+	}
+}
+
+func WithInsecure() MetricServiceOption {
+	return func(m *MetricService) {
+		m.exporterOptions = append(m.exporterOptions, otlpmetrichttp.WithInsecure())
+	}
+}
+
+func WithHeaders(headers map[string]string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.exporterOptions = append(m.exporterOptions, otlpmetrichttp.WithHeaders(headers))
+	}
+}
+
+func WithExporterOptions(options ...otlpmetrichttp.Option) MetricServiceOption {
+	return func(m *MetricService) {
+		m.exporterOptions = append(m.exporterOptions, options...)
+	}
+}
+
+func WithExportInterval(interval time.Duration) MetricServiceOption {
+	return func(m *MetricService) {
+		m.exportInterval = interval
+	}
+}
+
+func WithPushTimeout(timeout time.Duration) MetricServiceOption {
+	return func(m *MetricService) {
+		m.pushTimeout = timeout
+	}
+}
+
+func WithResourceAttribute(key, value string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.resourceAttributes = append(m.resourceAttributes, attribute.String(key, value))
+	}
+}
+
+func WithServiceName(name string) MetricServiceOption {
+	return WithResourceAttribute("service.name", name)
+}
+
+func WithMeterName(name string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.meterName = name
+	}
+}
+
+func WithReader(reader sdkmetric.Reader) MetricServiceOption {
+	return func(m *MetricService) {
+		m.reader = reader
+	}
+}
+
+func NewMetricService(options ...MetricServiceOption) *MetricService {
+
+	m := &MetricService{
+		meterName:      DefaultMeterName,
+		exportInterval: DefaultExportInterval,
+		pushTimeout:    DefaultPushTimeout,
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	if m.reader == nil {
+
+		exporter, err := otlpmetrichttp.New(context.Background(), m.exporterOptions...)
+		if err != nil {
+			panic(err)
+		}
+
+		m.reader = sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(m.exportInterval))
+
+	}
+
+	m.provider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(m.reader),
+		sdkmetric.WithResource(resource.NewSchemaless(m.resourceAttributes...)),
+	)
+
+	m.meter = m.provider.Meter(m.meterName)
+
+	return m
+
+}
+
+func (m *MetricService) Counter(options ...interface{}) metrics.Counter {
+	return NewCounter(m.meter, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) Gauge(options ...interface{}) metrics.Gauge {
+	return NewGauge(m.meter, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) Histogram(options ...interface{}) metrics.Histogram {
+	return NewHistogram(m.meter, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) GaugeFunc(function func() float64, options ...interface{}) {
+	NewGaugeFunc(m.meter, function, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) CounterFunc(function func() float64, options ...interface{}) {
+	NewCounterFunc(m.meter, function, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) Handler() http.Handler {
+	return http.NotFoundHandler()
+}
+
+func (m *MetricService) Push() error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.pushTimeout)
+	defer cancel()
+
+	return m.provider.ForceFlush(ctx)
+
+}
+
+func (m *MetricService) Shutdown(ctx context.Context) error {
+	return m.provider.Shutdown(ctx)
+}
+
+func (m *MetricService) Close() error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.pushTimeout)
+	defer cancel()
+
+	return m.Shutdown(ctx)
+
+}
+
+func (m *MetricService) MeterProvider() *sdkmetric.MeterProvider {
+	return m.provider
+}
+
+type Counter struct {
+	counter    metric.Float64Counter
+	labels     []string
+	attributes []attribute.KeyValue
+}
+
+func NewCounter(meter metric.Meter, options ...metrics.MetricOption) *Counter {
+
+	o := metrics.NewMetricOptionSet(options...)
+
+	counter, err := meter.Float64Counter(FullyQualifiedName(o), metric.WithDescription(o.Help))
+	if err != nil {
+		panic(err)
+	}
+
+	return &Counter{
+		counter:    counter,
+		labels:     o.Labels,
+		attributes: MapToAttributes(o.ConstLabels),
+	}
+
+}
+
+func (c *Counter) WithLabels(keysAndValues ...string) metrics.Counter {
+	return &Counter{
+		counter:    c.counter,
+		labels:     c.labels,
+		attributes: append(append([]attribute.KeyValue(nil), c.attributes...), StringSliceToAttributes(keysAndValues)...),
+	}
+}
+
+func (c *Counter) WithLabelValues(values ...string) metrics.Counter {
+	return &Counter{
+		counter:    c.counter,
+		labels:     c.labels,
+		attributes: append(append([]attribute.KeyValue(nil), c.attributes...), LabelValuesToAttributes(c.labels, values)...),
+	}
+}
+
+func (c *Counter) Add(value float64) {
+	c.AddWithContext(context.Background(), value)
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) AddWithExemplar(value float64, _ map[string]string) {
+	c.Add(value)
+}
+
+func (c *Counter) AddWithContext(ctx context.Context, value float64) {
+	c.counter.Add(ctx, value, metric.WithAttributes(c.attributes...))
+}
+
+type gaugeValue struct {
+	attributes attribute.Set
+	value      float64
+}
+
+type gaugeValues struct {
+	mutex  sync.Mutex
+	values map[attribute.Distinct]*gaugeValue
+}
+
+func (v *gaugeValues) update(attributes attribute.Set, update func(float64) float64) {
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	current, ok := v.values[attributes.Equivalent()]
+	if !ok {
+		current = &gaugeValue{attributes: attributes}
+		v.values[attributes.Equivalent()] = current
+	}
+
+	current.value = update(current.value)
+
+}
+
+func (v *gaugeValues) observe(_ context.Context, observer metric.Float64Observer) error {
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for _, current := range v.values {
+		observer.Observe(current.value, metric.WithAttributeSet(current.attributes))
+	}
+
+	return nil
+
+}
+
+type Gauge struct {
+	values     *gaugeValues
+	labels     []string
+	attributes []attribute.KeyValue
+}
+
+func NewGauge(meter metric.Meter, options ...metrics.MetricOption) *Gauge {
+
+	o := metrics.NewMetricOptionSet(options...)
+
+	g := &Gauge{
+		values:     &gaugeValues{values: make(map[attribute.Distinct]*gaugeValue)},
+		labels:     o.Labels,
+		attributes: MapToAttributes(o.ConstLabels),
+	}
+
+	_, err := meter.Float64ObservableGauge(
+		FullyQualifiedName(o),
+		metric.WithDescription(o.Help),
+		metric.WithFloat64Callback(g.values.observe),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return g
+
+}
+
+func (g *Gauge) WithLabels(keysAndValues ...string) metrics.Gauge {
+	return &Gauge{
+		values:     g.values,
+		labels:     g.labels,
+		attributes: append(append([]attribute.KeyValue(nil), g.attributes...), StringSliceToAttributes(keysAndValues)...),
+	}
+}
+
+func (g *Gauge) WithLabelValues(values ...string) metrics.Gauge {
+	return &Gauge{
+		values:     g.values,
+		labels:     g.labels,
+		attributes: append(append([]attribute.KeyValue(nil), g.attributes...), LabelValuesToAttributes(g.labels, values)...),
+	}
+}
+
+func (g *Gauge) Add(value float64) {
+	g.values.update(attribute.NewSet(g.attributes...), func(current float64) float64 {
+		return current + value
+	})
+}
+
+func (g *Gauge) Sub(value float64) {
+	g.values.update(attribute.NewSet(g.attributes...), func(current float64) float64 {
+		return current - value
+	})
+}
+
+func (g *Gauge) Set(value float64) {
+	g.values.update(attribute.NewSet(g.attributes...), func(float64) float64 {
+		return value
+	})
+}
+
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.Sub(1)
+}
+
+func (g *Gauge) SetToCurrentTime() {
+	g.Set(float64(time.Now().UnixNano()) / 1e9)
+}
+
+type Histogram struct {
+	histogram  metric.Float64Histogram
+	labels     []string
+	attributes []attribute.KeyValue
+}
+
+func NewHistogram(meter metric.Meter, options ...metrics.MetricOption) *Histogram {
+
+	o := metrics.NewMetricOptionSet(options...)
+
+	histogramOptions := []metric.Float64HistogramOption{
+		metric.WithDescription(o.Help),
+	}
+
+	if len(o.Buckets) > 0 {
+		histogramOptions = append(histogramOptions, metric.WithExplicitBucketBoundaries(o.Buckets...))
+	}
+
+	histogram, err := meter.Float64Histogram(FullyQualifiedName(o), histogramOptions...)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Histogram{
+		histogram:  histogram,
+		labels:     o.Labels,
+		attributes: MapToAttributes(o.ConstLabels),
+	}
+
+}
+
+func (h *Histogram) WithLabels(keysAndValues ...string) metrics.Histogram {
+	return &Histogram{
+		histogram:  h.histogram,
+		labels:     h.labels,
+		attributes: append(append([]attribute.KeyValue(nil), h.attributes...), StringSliceToAttributes(keysAndValues)...),
+	}
+}
+
+func (h *Histogram) WithLabelValues(values ...string) metrics.Histogram {
+	return &Histogram{
+		histogram:  h.histogram,
+		labels:     h.labels,
+		attributes: append(append([]attribute.KeyValue(nil), h.attributes...), LabelValuesToAttributes(h.labels, values)...),
+	}
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.ObserveWithContext(context.Background(), value)
+}
+
+func (h *Histogram) ObserveWithExemplar(value float64, _ map[string]string) {
+	h.Observe(value)
+}
+
+func (h *Histogram) ObserveWithContext(ctx context.Context, value float64) {
+	h.histogram.Record(ctx, value, metric.WithAttributes(h.attributes...))
+}
+
+func (h *Histogram) ObserveDuration(duration time.Duration) {
+	h.Observe(duration.Seconds())
+}
+
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (h *Histogram) StartTimer() *metrics.Timer {
+	return metrics.NewTimer(h)
+}
+
+func (h *Histogram) StartTimerWithContext(ctx context.Context) *metrics.Timer {
+	return metrics.NewTimerWithContext(ctx, h)
+}
+
+func NewGaugeFunc(meter metric.Meter, function func() float64, options ...metrics.MetricOption) metric.Float64ObservableGauge {
+
+	o := metrics.NewMetricOptionSet(options...)
+	attributes := metric.WithAttributes(MapToAttributes(o.ConstLabels)...)
+
+	gauge, err := meter.Float64ObservableGauge(
+		FullyQualifiedName(o),
+		metric.WithDescription(o.Help),
+		metric.WithFloat64Callback(func(_ context.Context, observer metric.Float64Observer) error {
+			observer.Observe(function(), attributes)
+			return nil
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return gauge
+
+}
+
+func NewCounterFunc(meter metric.Meter, function func() float64, options ...metrics.MetricOption) metric.Float64ObservableCounter {
+
+	o := metrics.NewMetricOptionSet(options...)
+	attributes := metric.WithAttributes(MapToAttributes(o.ConstLabels)...)
+
+	counter, err := meter.Float64ObservableCounter(
+		FullyQualifiedName(o),
+		metric.WithDescription(o.Help),
+		metric.WithFloat64Callback(func(_ context.Context, observer metric.Float64Observer) error {
+			observer.Observe(function(), attributes)
+			return nil
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return counter
+
+}
@@ -0,0 +1,200 @@
+package opentelemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+type receiver struct {
+	mutex    sync.Mutex
+	requests []*collectormetrics.ExportMetricsServiceRequest
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request := &collectormetrics.ExportMetricsServiceRequest{}
+
+	if err := proto.Unmarshal(body, request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.mutex.Lock()
+	r.requests = append(r.requests, request)
+	r.mutex.Unlock()
+
+	response, _ := proto.Marshal(&collectormetrics.ExportMetricsServiceResponse{})
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(response)
+
+}
+
+func (r *receiver) metrics() map[string]*metricsv1.Metric {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	received := make(map[string]*metricsv1.Metric)
+
+	for _, request := range r.requests {
+		for _, resourceMetrics := range request.ResourceMetrics {
+			for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+				for _, metric := range scopeMetrics.Metrics {
+					received[metric.Name] = metric
+				}
+			}
+		}
+	}
+
+	return received
+
+}
+
+func attributeValue(attributes []*commonv1.KeyValue, key string) string {
+
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value.GetStringValue()
+		}
+	}
+
+	return ""
+
+}
+
+func TestPushDeliversMetricsToReceiver(t *testing.T) {
+
+	r := &receiver{}
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	m := NewMetricService(
+		WithEndpointURL(server.URL+"/v1/metrics"),
+		WithServiceName("test"),
+	)
+
+	defer m.Shutdown(context.Background())
+
+	m.Counter(
+		metrics.WithName("requests_total"),
+		metrics.WithLabels([]string{"method"}),
+	).WithLabelValues("GET").Add(3)
+
+	m.Gauge(
+		metrics.WithName("queue_size"),
+	).Set(7)
+
+	histogram := m.Histogram(
+		metrics.WithName("request_duration_seconds"),
+		metrics.WithBuckets([]float64{0.1, 1}),
+	)
+
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+
+	if err := m.Push(); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	received := r.metrics()
+
+	counter, ok := received["requests_total"]
+	if !ok {
+		t.Fatalf("counter was not delivered, got %v", received)
+	}
+
+	points := counter.GetSum().GetDataPoints()
+	if len(points) != 1 || points[0].GetAsDouble() != 3 {
+		t.Errorf("unexpected counter data points: %v", points)
+	}
+
+	if method := attributeValue(points[0].Attributes, "method"); method != "GET" {
+		t.Errorf("expected method attribute GET, got %q", method)
+	}
+
+	gauge, ok := received["queue_size"]
+	if !ok {
+		t.Fatalf("gauge was not delivered, got %v", received)
+	}
+
+	if points := gauge.GetGauge().GetDataPoints(); len(points) != 1 || points[0].GetAsDouble() != 7 {
+		t.Errorf("unexpected gauge data points: %v", points)
+	}
+
+	hist, ok := received["request_duration_seconds"]
+	if !ok {
+		t.Fatalf("histogram was not delivered, got %v", received)
+	}
+
+	histogramPoints := hist.GetHistogram().GetDataPoints()
+	if len(histogramPoints) != 1 {
+		t.Fatalf("unexpected histogram data points: %v", histogramPoints)
+	}
+
+	if histogramPoints[0].Count != 2 || histogramPoints[0].GetSum() != 0.55 {
+		t.Errorf("unexpected histogram count %d and sum %g", histogramPoints[0].Count, histogramPoints[0].GetSum())
+	}
+
+	if bounds := histogramPoints[0].ExplicitBounds; len(bounds) != 2 || bounds[0] != 0.1 || bounds[1] != 1 {
+		t.Errorf("unexpected histogram bounds: %v", bounds)
+	}
+
+}
+
+func TestCloseFlushesMetricsRegisteredWithPrometheusOptions(t *testing.T) {
+
+	r := &receiver{}
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	m := NewMetricService(
+		WithEndpointURL(server.URL+"/v1/metrics"),
+		WithServiceName("test"),
+	)
+
+	m.Counter(
+		prometheus.WithNamespace("app"),
+		prometheus.WithName("jobs_total"),
+		prometheus.WithLabels([]string{"queue"}),
+		metrics.WithHelp("Jobs processed."),
+	).WithLabelValues("default").Inc()
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	counter, ok := r.metrics()["app_jobs_total"]
+	if !ok {
+		t.Fatalf("counter was not flushed on close, got %v", r.metrics())
+	}
+
+	if counter.Description != "Jobs processed." {
+		t.Errorf("unexpected description %q", counter.Description)
+	}
+
+	points := counter.GetSum().GetDataPoints()
+	if len(points) != 1 || attributeValue(points[0].Attributes, "queue") != "default" {
+		t.Errorf("unexpected counter data points: %v", points)
+	}
+
+}
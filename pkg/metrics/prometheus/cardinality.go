@@ -0,0 +1,147 @@
+package prometheus
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	OverflowLabelValue = "__overflow__"
+)
+
+type RejectedSeriesCounter struct {
+	once       sync.Once
+	registerer prometheus.Registerer
+	vec        *prometheus.CounterVec
+}
+
+func NewRejectedSeriesCounter(registerer prometheus.Registerer) *RejectedSeriesCounter {
+	return &RejectedSeriesCounter{
+		registerer: registerer,
+	}
+}
+
+func (r *RejectedSeriesCounter) Inc(metric string) {
+
+	r.once.Do(func() {
+		r.vec = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "metric_series_rejected_total",
+			Help: "Total number of label sets collapsed into the overflow series by the cardinality guard.",
+		}, []string{"metric"})
+		r.registerer.MustRegister(r.vec)
+	})
+
+	r.vec.WithLabelValues(metric).Inc()
+
+}
+
+type CardinalityGuard struct {
+	mutex    sync.Mutex
+	name     string
+	labels   []string
+	max      int
+	seen     map[string]struct{}
+	warned   bool
+	logging  logging.LoggingService
+	rejected *RejectedSeriesCounter
+}
+
+func NewCardinalityGuard(o *MetricOptionSet) *CardinalityGuard {
+
+	max := o.MaxSeries
+	if max <= 0 {
+		max = o.defaultMaxSeries
+	}
+
+	if max <= 0 || len(o.Labels) == 0 {
+		return nil
+	}
+
+	return &CardinalityGuard{
+		name:     prometheus.BuildFQName(o.Namespace, o.Subsystem, o.Name),
+		labels:   o.Labels,
+		max:      max,
+		seen:     make(map[string]struct{}),
+		logging:  o.logging,
+		rejected: o.rejected,
+	}
+
+}
+
+func (g *CardinalityGuard) admit(values []string) bool {
+
+	key := strings.Join(values, "\xff")
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, ok := g.seen[key]; ok {
+		return true
+	}
+
+	if len(g.seen) < g.max {
+		g.seen[key] = struct{}{}
+		return true
+	}
+
+	if g.rejected != nil {
+		g.rejected.Inc(g.name)
+	}
+
+	if !g.warned && g.logging != nil {
+		g.logging.Warn("metric series limit reached, collapsing new label values into overflow series",
+			"metric", g.name,
+			"limit", g.max,
+		)
+	}
+
+	g.warned = true
+
+	return false
+
+}
+
+func (g *CardinalityGuard) LabelValues(values []string) []string {
+
+	if g == nil || g.admit(values) {
+		return values
+	}
+
+	overflow := make([]string, len(values))
+
+	for i := range overflow {
+		overflow[i] = OverflowLabelValue
+	}
+
+	return overflow
+
+}
+
+func (g *CardinalityGuard) Labels(labels prometheus.Labels) prometheus.Labels {
+
+	if g == nil {
+		return labels
+	}
+
+	values := make([]string, len(g.labels))
+
+	for i, label := range g.labels {
+		values[i] = labels[label]
+	}
+
+	if g.admit(values) {
+		return labels
+	}
+
+	overflow := make(prometheus.Labels, len(labels))
+
+	for label := range labels {
+		overflow[label] = OverflowLabelValue
+	}
+
+	return overflow
+
+}
@@ -0,0 +1,184 @@
+package prometheus
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+type recordingLogger struct {
+	mutex    sync.Mutex
+	warnings []string
+}
+
+func (l *recordingLogger) Open() error {
+	return nil
+}
+
+func (l *recordingLogger) Close() error {
+	return nil
+}
+
+func (l *recordingLogger) Fatal(string, ...interface{}) {
+}
+
+func (l *recordingLogger) Error(string, ...interface{}) {
+}
+
+func (l *recordingLogger) Info(string, ...interface{}) {
+}
+
+func (l *recordingLogger) Debug(string, ...interface{}) {
+}
+
+func (l *recordingLogger) Warn(message string, _ ...interface{}) {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.warnings = append(l.warnings, message)
+
+}
+
+func (l *recordingLogger) Warnings() []string {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return append([]string(nil), l.warnings...)
+
+}
+
+func gather(t *testing.T, m *MetricService, name string) map[string]float64 {
+
+	t.Helper()
+
+	families, err := m.registry.Gather()
+	if err != nil {
+		t.Fatalf("gather failed: %v", err)
+	}
+
+	for _, family := range families {
+
+		if family.GetName() != name {
+			continue
+		}
+
+		values := make(map[string]float64)
+
+		for _, metric := range family.GetMetric() {
+
+			key := ""
+
+			for _, label := range metric.GetLabel() {
+				if len(key) > 0 {
+					key += ","
+				}
+				key += label.GetName() + "=" + label.GetValue()
+			}
+
+			values[key] = metric.GetCounter().GetValue()
+
+		}
+
+		return values
+
+	}
+
+	return nil
+
+}
+
+func TestCardinalityGuardCollapsesSeriesOverLimit(t *testing.T) {
+
+	logger := &recordingLogger{}
+
+	m := NewMetricService(WithMaxSeriesPerMetric(2), WithLogging(logger))
+
+	counter := m.Counter(
+		metrics.WithName("requests_total"),
+		metrics.WithHelp("Requests."),
+		metrics.WithLabels([]string{"path"}),
+	)
+
+	counter.WithLabelValues("/a").Inc()
+	counter.WithLabelValues("/b").Inc()
+
+	if rejected := gather(t, m, "metric_series_rejected_total"); rejected != nil {
+		t.Fatalf("expected rejected counter to be registered lazily, got %v", rejected)
+	}
+
+	counter.WithLabelValues("/c").Inc()
+	counter.WithLabels("path", "/d").Inc()
+	counter.WithLabelValues("/a").Inc()
+
+	expected := map[string]float64{
+		"path=/a":                    2,
+		"path=/b":                    1,
+		"path=" + OverflowLabelValue: 2,
+	}
+
+	series := gather(t, m, "requests_total")
+
+	if len(series) != len(expected) {
+		t.Errorf("expected series %v, got %v", expected, series)
+	}
+
+	for key, value := range expected {
+		if series[key] != value {
+			t.Errorf("expected %s to be %g, got %g", key, value, series[key])
+		}
+	}
+
+	if rejected := gather(t, m, "metric_series_rejected_total")["metric=requests_total"]; rejected != 2 {
+		t.Errorf("expected 2 rejected series, got %g", rejected)
+	}
+
+	if warnings := logger.Warnings(); len(warnings) != 1 {
+		t.Errorf("expected a single warning, got %q", warnings)
+	}
+
+}
+
+func TestCardinalityGuardPerMetricLimit(t *testing.T) {
+
+	m := NewMetricService(WithMaxSeriesPerMetric(10))
+
+	counter := m.Counter(
+		metrics.WithName("jobs_total"),
+		metrics.WithHelp("Jobs."),
+		metrics.WithLabels([]string{"queue"}),
+		WithMaxSeries(1),
+	)
+
+	counter.WithLabelValues("a").Inc()
+	counter.WithLabelValues("b").Inc()
+
+	series := gather(t, m, "jobs_total")
+
+	if series["queue=a"] != 1 || series["queue="+OverflowLabelValue] != 1 {
+		t.Errorf("expected per-metric limit of 1 to apply, got %v", series)
+	}
+
+}
+
+func TestCardinalityGuardDisabledByDefault(t *testing.T) {
+
+	m := NewMetricService()
+
+	counter := m.Counter(
+		metrics.WithName("jobs_total"),
+		metrics.WithHelp("Jobs."),
+		metrics.WithLabels([]string{"queue"}),
+	)
+
+	for _, queue := range []string{"a", "b", "c"} {
+		counter.WithLabelValues(queue).Inc()
+	}
+
+	if series := gather(t, m, "jobs_total"); len(series) != 3 {
+		t.Errorf("expected 3 series without a limit, got %v", series)
+	}
+
+}
@@ -0,0 +1,161 @@
+package prometheus
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+type LintMode int
+
+const (
+	LintDisabled LintMode = iota
+	LintWarn
+	LintReject
+)
+
+var (
+	MetricNamePattern = regexp.MustCompile(`^[a-z_:][a-z0-9_:]*$`)
+	LabelNamePattern  = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+	BaseUnitSuffixes = []string{
+		"_seconds", "_bytes", "_ratio", "_meters", "_grams", "_celsius",
+		"_volts", "_amperes", "_joules", "_bits",
+	}
+
+	NonBaseUnits = []NonBaseUnit{
+		{Suffix: "_days", Base: "_seconds"},
+		{Suffix: "_gigabytes", Base: "_bytes"},
+		{Suffix: "_hours", Base: "_seconds"},
+		{Suffix: "_kilobytes", Base: "_bytes"},
+		{Suffix: "_megabytes", Base: "_bytes"},
+		{Suffix: "_microseconds", Base: "_seconds"},
+		{Suffix: "_milliseconds", Base: "_seconds"},
+		{Suffix: "_minutes", Base: "_seconds"},
+		{Suffix: "_ms", Base: "_seconds"},
+		{Suffix: "_nanoseconds", Base: "_seconds"},
+		{Suffix: "_percent", Base: "_ratio"},
+		{Suffix: "_percentage", Base: "_ratio"},
+	}
+
+	ReservedSuffixes = []string{"_bucket", "_count", "_sum"}
+)
+
+type NonBaseUnit struct {
+	Suffix string
+	Base   string
+}
+
+func LintMetric(description metrics.MetricDescription) []string {
+
+	var problems []string
+
+	name := description.Name
+
+	if !MetricNamePattern.MatchString(name) {
+		problems = append(problems, "name must be snake_case and match "+MetricNamePattern.String())
+	}
+
+	if len(description.Help) == 0 {
+		problems = append(problems, "help text is empty")
+	}
+
+	switch description.Type {
+	case metrics.CounterType:
+		if !strings.HasSuffix(name, "_total") {
+			problems = append(problems, "counter name must end with _total")
+		}
+	default:
+		if strings.HasSuffix(name, "_total") {
+			problems = append(problems, description.Type+" name must not end with _total")
+		}
+	}
+
+	unitName := strings.TrimSuffix(name, "_total")
+
+	for _, unit := range NonBaseUnits {
+		if strings.HasSuffix(unitName, unit.Suffix) || strings.Contains(unitName, unit.Suffix+"_") {
+			problems = append(problems, fmt.Sprintf("use base unit %s instead of %s", unit.Base, unit.Suffix))
+		}
+	}
+
+	if description.Type == metrics.HistogramType && !hasSuffix(unitName, BaseUnitSuffixes) {
+		problems = append(problems, "histogram name should end with a base unit suffix such as _seconds or _bytes")
+	}
+
+	for _, suffix := range ReservedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			problems = append(problems, "name must not end with reserved suffix "+suffix)
+		}
+	}
+
+	labels := append([]string(nil), description.Labels...)
+
+	for label := range description.ConstLabels {
+		labels = append(labels, label)
+	}
+
+	sort.Strings(labels[len(description.Labels):])
+
+	for _, label := range labels {
+
+		if !LabelNamePattern.MatchString(label) {
+			problems = append(problems, fmt.Sprintf("label %q must be snake_case and match %s", label, LabelNamePattern.String()))
+		}
+
+		if strings.HasPrefix(label, "__") {
+			problems = append(problems, fmt.Sprintf("label %q uses the reserved __ prefix", label))
+		}
+
+		if description.Type == metrics.HistogramType && label == "le" {
+			problems = append(problems, `label "le" is reserved for histogram buckets`)
+		}
+
+		if label == "quantile" {
+			problems = append(problems, `label "quantile" is reserved for summaries`)
+		}
+
+	}
+
+	return problems
+
+}
+
+func hasSuffix(name string, suffixes []string) bool {
+
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func (o *MetricOptionSet) lint(description metrics.MetricDescription) {
+
+	if o.lintMode == LintDisabled {
+		return
+	}
+
+	problems := LintMetric(description)
+	if len(problems) == 0 {
+		return
+	}
+
+	if o.lintMode == LintReject {
+		panic(fmt.Errorf("metric %q violates naming conventions: %s", description.Name, strings.Join(problems, "; ")))
+	}
+
+	if o.logging != nil {
+		o.logging.Warn("metric violates naming conventions",
+			"metric", description.Name,
+			"problems", problems,
+		)
+	}
+
+}
@@ -0,0 +1,153 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+func TestLintMetric(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		description metrics.MetricDescription
+		problems    []string
+	}{
+		{
+			name: "valid counter",
+			description: metrics.MetricDescription{
+				Name:   "http_requests_total",
+				Type:   metrics.CounterType,
+				Help:   "Requests.",
+				Labels: []string{"method"},
+			},
+		},
+		{
+			name: "valid histogram",
+			description: metrics.MetricDescription{
+				Name: "http_request_duration_seconds",
+				Type: metrics.HistogramType,
+				Help: "Duration.",
+			},
+		},
+		{
+			name: "counter without _total and empty help",
+			description: metrics.MetricDescription{
+				Name: "http_requests",
+				Type: metrics.CounterType,
+			},
+			problems: []string{"help text is empty", "counter name must end with _total"},
+		},
+		{
+			name: "gauge with _total and camel case",
+			description: metrics.MetricDescription{
+				Name: "queueSize_total",
+				Type: metrics.GaugeType,
+				Help: "Queue.",
+			},
+			problems: []string{"name must be snake_case", "gauge name must not end with _total"},
+		},
+		{
+			name: "non base unit",
+			description: metrics.MetricDescription{
+				Name: "request_duration_milliseconds",
+				Type: metrics.HistogramType,
+				Help: "Duration.",
+			},
+			problems: []string{"use base unit _seconds instead of _milliseconds", "histogram name should end with a base unit suffix"},
+		},
+		{
+			name: "reserved suffix",
+			description: metrics.MetricDescription{
+				Name: "jobs_count",
+				Type: metrics.GaugeType,
+				Help: "Jobs.",
+			},
+			problems: []string{"name must not end with reserved suffix _count"},
+		},
+		{
+			name: "info gauge is allowed",
+			description: metrics.MetricDescription{
+				Name: "build_info",
+				Type: metrics.GaugeType,
+				Help: "Build.",
+			},
+		},
+		{
+			name: "reserved labels",
+			description: metrics.MetricDescription{
+				Name:        "latency_seconds",
+				Type:        metrics.HistogramType,
+				Help:        "Latency.",
+				Labels:      []string{"le", "Method"},
+				ConstLabels: map[string]string{"__internal": "x", "quantile": "0.5"},
+			},
+			problems: []string{
+				`label "le" is reserved for histogram buckets`,
+				`label "Method" must be snake_case`,
+				`label "__internal" uses the reserved __ prefix`,
+				`label "quantile" is reserved for summaries`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			problems := LintMetric(test.description)
+
+			if len(problems) != len(test.problems) {
+				t.Fatalf("expected %d problems, got %q", len(test.problems), problems)
+			}
+
+			for i, expected := range test.problems {
+				if !strings.HasPrefix(problems[i], expected) {
+					t.Errorf("expected problem %d to start with %q, got %q", i, expected, problems[i])
+				}
+			}
+
+		})
+	}
+
+}
+
+func TestLintRejectModePanics(t *testing.T) {
+
+	m := NewMetricService(WithStrictNaming())
+
+	m.Counter(metrics.WithName("jobs_total"), metrics.WithHelp("Jobs."))
+
+	defer func() {
+
+		if recover() == nil {
+			t.Fatal("expected invalid metric to be rejected")
+		}
+
+		if gather(t, m, "jobs") != nil {
+			t.Error("expected rejected metric not to be registered")
+		}
+
+	}()
+
+	m.Counter(metrics.WithName("jobs"), metrics.WithHelp("Jobs."))
+
+}
+
+func TestLintWarnModeRegistersMetric(t *testing.T) {
+
+	logger := &recordingLogger{}
+
+	m := NewMetricService(WithLintMode(LintWarn), WithLogging(logger))
+
+	m.Counter(metrics.WithName("jobs")).Inc()
+
+	if warnings := logger.Warnings(); len(warnings) != 1 {
+		t.Errorf("expected a naming warning, got %q", warnings)
+	}
+
+	if gather(t, m, "jobs")[""] != 1 {
+		t.Error("expected metric to be registered despite the warning")
+	}
+
+}
@@ -1,9 +1,13 @@
 package prometheus
 
 import (
+	"context"
 	"net/http"
+	"os"
 	"time"
+	"unicode/utf8"
 
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
 	"github.com/definancialbr/golang-container-kit/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -15,13 +19,88 @@ func InterfaceSliceToMetricOptionSlice(interfaceOptions []interface{}) []MetricO
 	options := make([]MetricOption, len(interfaceOptions))
 
 	for i, interfaceOption := range interfaceOptions {
-		options[i] = interfaceOption.(MetricOption)
+		switch option := interfaceOption.(type) {
+		case metrics.MetricOption:
+			options[i] = FromMetricOption(option)
+		default:
+			options[i] = interfaceOption.(MetricOption)
+		}
 	}
 
 	return options
 
 }
 
+func FromMetricOption(option metrics.MetricOption) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+
+		o := &metrics.MetricOptionSet{
+			Namespace:   optionSet.Namespace,
+			Subsystem:   optionSet.Subsystem,
+			Name:        optionSet.Name,
+			Help:        optionSet.Help,
+			Buckets:     optionSet.Buckets,
+			Labels:      optionSet.Labels,
+			ConstLabels: optionSet.ConstLabels,
+		}
+
+		option(o)
+
+		optionSet.Namespace = o.Namespace
+		optionSet.Subsystem = o.Subsystem
+		optionSet.Name = o.Name
+		optionSet.Help = o.Help
+		optionSet.Buckets = o.Buckets
+		optionSet.Labels = o.Labels
+		optionSet.ConstLabels = o.ConstLabels
+
+	}
+}
+
+func ToMetricOption(option MetricOption) metrics.MetricOption {
+	return func(o *metrics.MetricOptionSet) {
+
+		optionSet := &MetricOptionSet{
+			Opts: prometheus.Opts{
+				Namespace:   o.Namespace,
+				Subsystem:   o.Subsystem,
+				Name:        o.Name,
+				Help:        o.Help,
+				ConstLabels: o.ConstLabels,
+			},
+			Buckets: o.Buckets,
+			Labels:  o.Labels,
+		}
+
+		option(optionSet)
+
+		o.Namespace = optionSet.Namespace
+		o.Subsystem = optionSet.Subsystem
+		o.Name = optionSet.Name
+		o.Help = optionSet.Help
+		o.Buckets = optionSet.Buckets
+		o.Labels = optionSet.Labels
+		o.ConstLabels = optionSet.ConstLabels
+
+	}
+}
+
+func ExemplarToPrometheusLabels(exemplar map[string]string) prometheus.Labels {
+
+	runes := 0
+
+	for key, value := range exemplar {
+		runes += utf8.RuneCountInString(key) + utf8.RuneCountInString(value)
+	}
+
+	if len(exemplar) == 0 || runes > prometheus.ExemplarMaxRunes {
+		return nil
+	}
+
+	return prometheus.Labels(exemplar)
+
+}
+
 func StringSliceToPrometheusLabels(keysAndValues []string) prometheus.Labels {
 
 	labels := make(prometheus.Labels)
@@ -41,15 +120,28 @@ var (
 		MaxRequestsInFlight: 10,
 		EnableOpenMetrics:   true,
 	}
+
+	DefaultKubernetesLabelEnvs = map[string]string{
+		"pod":       "POD_NAME",
+		"namespace": "POD_NAMESPACE",
+		"node":      "NODE_NAME",
+	}
 )
 
 type MetricServiceOption func(*MetricService)
 
 type MetricService struct {
 	registry           *prometheus.Registry
+	registerer         prometheus.Registerer
+	globalLabels       prometheus.Labels
 	httpHandlerOptions promhttp.HandlerOpts
 	pusher             *push.Pusher
 	pusherGroupings    map[string]string
+	maxSeries          int
+	logging            logging.LoggingService
+	rejected           *RejectedSeriesCounter
+	catalog            *metrics.Catalog
+	lintMode           LintMode
 }
 
 func WithHttpHandlerOptions(httpHandlerOptions promhttp.HandlerOpts) MetricServiceOption {
@@ -70,18 +162,73 @@ func WithPusherGrouping(key, value string) MetricServiceOption {
 	}
 }
 
+func WithGlobalLabel(key, value string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.globalLabels[key] = value
+	}
+}
+
+func WithGlobalLabels(labels map[string]string) MetricServiceOption {
+	return func(m *MetricService) {
+		for key, value := range labels {
+			m.globalLabels[key] = value
+		}
+	}
+}
+
+func WithGlobalLabelsFromEnv(labelsToEnvs map[string]string) MetricServiceOption {
+	return func(m *MetricService) {
+		for key, env := range labelsToEnvs {
+			if value := os.Getenv(env); len(value) > 0 {
+				m.globalLabels[key] = value
+			}
+		}
+	}
+}
+
+func WithKubernetesGlobalLabels() MetricServiceOption {
+	return WithGlobalLabelsFromEnv(DefaultKubernetesLabelEnvs)
+}
+
+func WithMaxSeriesPerMetric(maxSeries int) MetricServiceOption {
+	return func(m *MetricService) {
+		m.maxSeries = maxSeries
+	}
+}
+
+func WithLogging(loggingService logging.LoggingService) MetricServiceOption {
+	return func(m *MetricService) {
+		m.logging = loggingService
+	}
+}
+
+func WithLintMode(lintMode LintMode) MetricServiceOption {
+	return func(m *MetricService) {
+		m.lintMode = lintMode
+	}
+}
+
+func WithStrictNaming() MetricServiceOption {
+	return WithLintMode(LintReject)
+}
+
 func NewMetricService(options ...MetricServiceOption) *MetricService {
 
 	m := &MetricService{
 		registry:           prometheus.NewRegistry(),
+		globalLabels:       make(prometheus.Labels),
 		httpHandlerOptions: DefaultHttpHandlerOptions,
 		pusherGroupings:    make(map[string]string),
+		catalog:            metrics.NewCatalog(),
 	}
 
 	for _, option := range options {
 		option(m)
 	}
 
+	m.registerer = prometheus.WrapRegistererWith(m.globalLabels, m.registry)
+	m.rejected = NewRejectedSeriesCounter(m.registerer)
+
 	if m.pusher != nil {
 
 		m.pusher = m.pusher.Gatherer(m.registry)
@@ -98,7 +245,7 @@ func NewMetricService(options ...MetricServiceOption) *MetricService {
 
 func (m *MetricService) Counter(options ...interface{}) metrics.Counter {
 
-	options = append(options, WithRegistry(m.registry))
+	options = append(options, WithRegisterer(m.registerer), m.withServiceDefaults())
 	counter := NewCounter(InterfaceSliceToMetricOptionSlice(options)...)
 
 	return counter
@@ -107,7 +254,7 @@ func (m *MetricService) Counter(options ...interface{}) metrics.Counter {
 
 func (m *MetricService) Gauge(options ...interface{}) metrics.Gauge {
 
-	options = append(options, WithRegistry(m.registry))
+	options = append(options, WithRegisterer(m.registerer), m.withServiceDefaults())
 	gauge := NewGauge(InterfaceSliceToMetricOptionSlice(options)...)
 
 	return gauge
@@ -116,28 +263,84 @@ func (m *MetricService) Gauge(options ...interface{}) metrics.Gauge {
 
 func (m *MetricService) Histogram(options ...interface{}) metrics.Histogram {
 
-	options = append(options, WithRegistry(m.registry))
+	options = append(options, WithRegisterer(m.registerer), m.withServiceDefaults())
 	histogram := NewHistogram(InterfaceSliceToMetricOptionSlice(options)...)
 
 	return histogram
 
 }
 
+func (m *MetricService) GaugeFunc(function func() float64, options ...interface{}) {
+
+	options = append(options, WithRegisterer(m.registerer), m.withServiceDefaults())
+	NewGaugeFunc(function, InterfaceSliceToMetricOptionSlice(options)...)
+
+}
+
+func (m *MetricService) CounterFunc(function func() float64, options ...interface{}) {
+
+	options = append(options, WithRegisterer(m.registerer), m.withServiceDefaults())
+	NewCounterFunc(function, InterfaceSliceToMetricOptionSlice(options)...)
+
+}
+
+func (m *MetricService) Register(collector prometheus.Collector) error {
+	return m.registerer.Register(collector)
+}
+
+func (m *MetricService) MustRegister(collectors ...prometheus.Collector) {
+	m.registerer.MustRegister(collectors...)
+}
+
+func (m *MetricService) Unregister(collector prometheus.Collector) bool {
+	return m.registerer.Unregister(collector)
+}
+
+func (m *MetricService) withServiceDefaults() MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.defaultMaxSeries = m.maxSeries
+		optionSet.logging = m.logging
+		optionSet.rejected = m.rejected
+		optionSet.catalog = m.catalog
+		optionSet.lintMode = m.lintMode
+	}
+}
+
+func (m *MetricService) Catalog() *metrics.Catalog {
+	return m.catalog
+}
+
+func (m *MetricService) CatalogHandler() http.Handler {
+	return m.catalog.Handler()
+}
+
 func (m *MetricService) Handler() http.Handler {
-	return promhttp.InstrumentMetricHandler(m.registry, promhttp.HandlerFor(m.registry, m.httpHandlerOptions))
+	return promhttp.InstrumentMetricHandler(m.registerer, promhttp.HandlerFor(m.registry, m.httpHandlerOptions))
 }
 
 func (m *MetricService) Push() error {
+
+	if m.pusher == nil {
+		return nil
+	}
+
 	return m.pusher.Push()
+
 }
 
 type MetricOption func(*MetricOptionSet)
 
 type MetricOptionSet struct {
 	prometheus.Opts
-	Buckets  []float64
-	Labels   []string
-	registry *prometheus.Registry
+	Buckets          []float64
+	Labels           []string
+	MaxSeries        int
+	registerer       prometheus.Registerer
+	defaultMaxSeries int
+	logging          logging.LoggingService
+	rejected         *RejectedSeriesCounter
+	catalog          *metrics.Catalog
+	lintMode         LintMode
 }
 
 func WithNamespace(namespace string) MetricOption {
@@ -176,9 +379,38 @@ func WithLabels(labels []string) MetricOption {
 	}
 }
 
+func WithMaxSeries(maxSeries int) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.MaxSeries = maxSeries
+	}
+}
+
+func WithConstLabel(key, value string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		if optionSet.ConstLabels == nil {
+			optionSet.ConstLabels = make(prometheus.Labels)
+		}
+		optionSet.ConstLabels[key] = value
+	}
+}
+
+func WithConstLabels(labels map[string]string) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		for key, value := range labels {
+			WithConstLabel(key, value)(optionSet)
+		}
+	}
+}
+
 func WithRegistry(registry *prometheus.Registry) MetricOption {
 	return func(optionSet *MetricOptionSet) {
-		optionSet.registry = registry
+		optionSet.registerer = registry
+	}
+}
+
+func WithRegisterer(registerer prometheus.Registerer) MetricOption {
+	return func(optionSet *MetricOptionSet) {
+		optionSet.registerer = registerer
 	}
 }
 
@@ -194,6 +426,34 @@ func NewMetricOptionSet(options ...MetricOption) *MetricOptionSet {
 
 }
 
+func (o *MetricOptionSet) Describe(metricType string) metrics.MetricDescription {
+
+	description := metrics.MetricDescription{
+		Name:        prometheus.BuildFQName(o.Namespace, o.Subsystem, o.Name),
+		Type:        metricType,
+		Help:        o.Help,
+		Labels:      o.Labels,
+		ConstLabels: o.ConstLabels,
+		Buckets:     o.Buckets,
+	}
+
+	if metricType == metrics.HistogramType && len(description.Buckets) == 0 {
+		description.Buckets = prometheus.DefBuckets
+	}
+
+	return description
+
+}
+
+func (o *MetricOptionSet) inspect(metricType string) {
+
+	description := o.Describe(metricType)
+
+	o.lint(description)
+	o.catalog.Add(description)
+
+}
+
 func (o *MetricOptionSet) AsCounterOpts() prometheus.CounterOpts {
 	return prometheus.CounterOpts(o.Opts)
 }
@@ -204,32 +464,36 @@ func (o *MetricOptionSet) AsGaugeOpts() prometheus.GaugeOpts {
 
 func (o *MetricOptionSet) AsHistogramOpts() prometheus.HistogramOpts {
 	return prometheus.HistogramOpts{
-		Namespace: o.Namespace,
-		Subsystem: o.Subsystem,
-		Name:      o.Name,
-		Help:      o.Help,
-		Buckets:   o.Buckets,
+		Namespace:   o.Namespace,
+		Subsystem:   o.Subsystem,
+		Name:        o.Name,
+		Help:        o.Help,
+		ConstLabels: o.ConstLabels,
+		Buckets:     o.Buckets,
 	}
 }
 
 type Counter struct {
 	vec     *prometheus.CounterVec
 	counter prometheus.Counter
+	guard   *CardinalityGuard
 }
 
 func NewCounter(options ...MetricOption) *Counter {
 
 	o := NewMetricOptionSet(options...)
+	o.inspect(metrics.CounterType)
 	c := &Counter{}
 
 	if len(o.Labels) > 0 {
 		c.vec = prometheus.NewCounterVec(o.AsCounterOpts(), o.Labels)
-		o.registry.MustRegister(c.vec)
+		c.guard = NewCardinalityGuard(o)
+		o.registerer.MustRegister(c.vec)
 		return c
 	}
 
 	c.counter = prometheus.NewCounter(o.AsCounterOpts())
-	o.registry.MustRegister(c.counter)
+	o.registerer.MustRegister(c.counter)
 
 	return c
 
@@ -241,7 +505,8 @@ func (c *Counter) WithLabels(keysAndValues ...string) metrics.Counter {
 
 	return &Counter{
 		vec:     c.vec,
-		counter: c.vec.With(labels),
+		counter: c.vec.With(c.guard.Labels(labels)),
+		guard:   c.guard,
 	}
 
 }
@@ -250,7 +515,8 @@ func (c *Counter) WithLabelValues(values ...string) metrics.Counter {
 
 	return &Counter{
 		vec:     c.vec,
-		counter: c.vec.WithLabelValues(values...),
+		counter: c.vec.WithLabelValues(c.guard.LabelValues(values)...),
+		guard:   c.guard,
 	}
 
 }
@@ -259,24 +525,48 @@ func (c *Counter) Add(value float64) {
 	c.counter.Add(value)
 }
 
+func (c *Counter) Inc() {
+	c.counter.Inc()
+}
+
+func (c *Counter) AddWithExemplar(value float64, exemplar map[string]string) {
+
+	labels := ExemplarToPrometheusLabels(exemplar)
+
+	if adder, ok := c.counter.(prometheus.ExemplarAdder); ok && labels != nil {
+		adder.AddWithExemplar(value, labels)
+		return
+	}
+
+	c.counter.Add(value)
+
+}
+
+func (c *Counter) AddWithContext(ctx context.Context, value float64) {
+	c.AddWithExemplar(value, metrics.ExemplarFromContext(ctx))
+}
+
 type Gauge struct {
 	vec   *prometheus.GaugeVec
 	gauge prometheus.Gauge
+	guard *CardinalityGuard
 }
 
 func NewGauge(options ...MetricOption) *Gauge {
 
 	o := NewMetricOptionSet(options...)
+	o.inspect(metrics.GaugeType)
 	g := &Gauge{}
 
 	if len(o.Labels) > 0 {
 		g.vec = prometheus.NewGaugeVec(o.AsGaugeOpts(), o.Labels)
-		o.registry.MustRegister(g.vec)
+		g.guard = NewCardinalityGuard(o)
+		o.registerer.MustRegister(g.vec)
 		return g
 	}
 
 	g.gauge = prometheus.NewGauge(o.AsGaugeOpts())
-	o.registry.MustRegister(g.gauge)
+	o.registerer.MustRegister(g.gauge)
 
 	return g
 
@@ -288,7 +578,8 @@ func (g *Gauge) WithLabels(keysAndValues ...string) metrics.Gauge {
 
 	return &Gauge{
 		vec:   g.vec,
-		gauge: g.vec.With(labels),
+		gauge: g.vec.With(g.guard.Labels(labels)),
+		guard: g.guard,
 	}
 
 }
@@ -297,7 +588,8 @@ func (g *Gauge) WithLabelValues(values ...string) metrics.Gauge {
 
 	return &Gauge{
 		vec:   g.vec,
-		gauge: g.vec.WithLabelValues(values...),
+		gauge: g.vec.WithLabelValues(g.guard.LabelValues(values)...),
+		guard: g.guard,
 	}
 
 }
@@ -314,25 +606,40 @@ func (g *Gauge) Set(value float64) {
 	g.gauge.Set(value)
 }
 
+func (g *Gauge) Inc() {
+	g.gauge.Inc()
+}
+
+func (g *Gauge) Dec() {
+	g.gauge.Dec()
+}
+
+func (g *Gauge) SetToCurrentTime() {
+	g.gauge.SetToCurrentTime()
+}
+
 type Histogram struct {
 	vec      *prometheus.HistogramVec
 	observer prometheus.Observer
+	guard    *CardinalityGuard
 }
 
 func NewHistogram(options ...MetricOption) *Histogram {
 
 	o := NewMetricOptionSet(options...)
+	o.inspect(metrics.HistogramType)
 	h := &Histogram{}
 
 	if len(o.Labels) > 0 {
 		h.vec = prometheus.NewHistogramVec(o.AsHistogramOpts(), o.Labels)
-		o.registry.MustRegister(h.vec)
+		h.guard = NewCardinalityGuard(o)
+		o.registerer.MustRegister(h.vec)
 		return h
 	}
 
 	histogram := prometheus.NewHistogram(o.AsHistogramOpts())
 	h.observer = histogram
-	o.registry.MustRegister(histogram)
+	o.registerer.MustRegister(histogram)
 
 	return h
 
@@ -344,7 +651,8 @@ func (g *Histogram) WithLabels(keysAndValues ...string) metrics.Histogram {
 
 	return &Histogram{
 		vec:      g.vec,
-		observer: g.vec.With(labels),
+		observer: g.vec.With(g.guard.Labels(labels)),
+		guard:    g.guard,
 	}
 
 }
@@ -353,7 +661,8 @@ func (g *Histogram) WithLabelValues(values ...string) metrics.Histogram {
 
 	return &Histogram{
 		vec:      g.vec,
-		observer: g.vec.WithLabelValues(values...),
+		observer: g.vec.WithLabelValues(g.guard.LabelValues(values)...),
+		guard:    g.guard,
 	}
 
 }
@@ -361,3 +670,60 @@ func (g *Histogram) WithLabelValues(values ...string) metrics.Histogram {
 func (g *Histogram) Observe(value float64) {
 	g.observer.Observe(value)
 }
+
+func (g *Histogram) ObserveWithExemplar(value float64, exemplar map[string]string) {
+
+	labels := ExemplarToPrometheusLabels(exemplar)
+
+	if observer, ok := g.observer.(prometheus.ExemplarObserver); ok && labels != nil {
+		observer.ObserveWithExemplar(value, labels)
+		return
+	}
+
+	g.observer.Observe(value)
+
+}
+
+func (g *Histogram) ObserveWithContext(ctx context.Context, value float64) {
+	g.ObserveWithExemplar(value, metrics.ExemplarFromContext(ctx))
+}
+
+func (g *Histogram) ObserveDuration(duration time.Duration) {
+	g.Observe(duration.Seconds())
+}
+
+func (g *Histogram) ObserveSince(start time.Time) {
+	g.Observe(time.Since(start).Seconds())
+}
+
+func (g *Histogram) StartTimer() *metrics.Timer {
+	return metrics.NewTimer(g)
+}
+
+func (g *Histogram) StartTimerWithContext(ctx context.Context) *metrics.Timer {
+	return metrics.NewTimerWithContext(ctx, g)
+}
+
+func NewGaugeFunc(function func() float64, options ...MetricOption) prometheus.GaugeFunc {
+
+	o := NewMetricOptionSet(options...)
+	o.inspect(metrics.GaugeType)
+
+	gauge := prometheus.NewGaugeFunc(o.AsGaugeOpts(), function)
+	o.registerer.MustRegister(gauge)
+
+	return gauge
+
+}
+
+func NewCounterFunc(function func() float64, options ...MetricOption) prometheus.CounterFunc {
+
+	o := NewMetricOptionSet(options...)
+	o.inspect(metrics.CounterType)
+
+	counter := prometheus.NewCounterFunc(o.AsCounterOpts(), function)
+	o.registerer.MustRegister(counter)
+
+	return counter
+
+}
@@ -0,0 +1,748 @@
+package statsd
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+)
+
+const (
+	CounterType      = "c"
+	GaugeType        = "g"
+	HistogramType    = "h"
+	DistributionType = "d"
+)
+
+var (
+	DefaultAddress       = "127.0.0.1:8125"
+	DefaultFlushInterval = 10 * time.Second
+	DefaultMaxPacketSize = 1432
+	DefaultMaxSamples    = 1000
+)
+
+func InterfaceSliceToMetricOptionSlice(interfaceOptions []interface{}) []metrics.MetricOption {
+
+	options := make([]metrics.MetricOption, len(interfaceOptions))
+
+	for i, interfaceOption := range interfaceOptions {
+		switch option := interfaceOption.(type) {
+		case prometheus.MetricOption:
+			options[i] = prometheus.ToMetricOption(option)
+		default:
+			options[i] = interfaceOption.(metrics.MetricOption)
+		}
+	}
+
+	return options
+
+}
+
+func StringSliceToTags(keysAndValues []string) []string {
+
+	tags := make([]string, 0, len(keysAndValues)/2)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		tags = append(tags, keysAndValues[i]+":"+keysAndValues[i+1])
+	}
+
+	return tags
+
+}
+
+func LabelValuesToTags(labels []string, values []string) []string {
+
+	tags := make([]string, 0, len(labels))
+
+	for i := 0; i < len(labels) && i < len(values); i++ {
+		tags = append(tags, labels[i]+":"+values[i])
+	}
+
+	return tags
+
+}
+
+func MapToTags(labels map[string]string) []string {
+
+	tags := make([]string, 0, len(labels))
+
+	for key, value := range labels {
+		tags = append(tags, key+":"+value)
+	}
+
+	return tags
+
+}
+
+func FullyQualifiedName(o *metrics.MetricOptionSet) string {
+
+	parts := make([]string, 0, 3)
+
+	for _, part := range []string{o.Namespace, o.Subsystem, o.Name} {
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ".")
+
+}
+
+func AgentAddressFromEnv() string {
+
+	host := os.Getenv("DD_AGENT_HOST")
+	if len(host) == 0 {
+		return DefaultAddress
+	}
+
+	port := os.Getenv("DD_DOGSTATSD_PORT")
+	if len(port) == 0 {
+		port = "8125"
+	}
+
+	return net.JoinHostPort(host, port)
+
+}
+
+type series struct {
+	name string
+	tags []string
+}
+
+func newSeries(name string, tags []string) series {
+
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+
+	return series{name: name, tags: sorted}
+
+}
+
+func (s series) key() string {
+	return s.name + "|" + strings.Join(s.tags, ",")
+}
+
+type aggregate struct {
+	series
+	value float64
+	dirty bool
+}
+
+type samples struct {
+	series
+	values []float64
+	count  int
+}
+
+func (s *samples) add(value float64, maxSamples int) {
+
+	s.count++
+
+	if maxSamples <= 0 || len(s.values) < maxSamples {
+		s.values = append(s.values, value)
+		return
+	}
+
+	if i := rand.Intn(s.count); i < maxSamples {
+		s.values[i] = value
+	}
+
+}
+
+func (s *samples) rate() float64 {
+
+	if s.count <= len(s.values) {
+		return 1
+	}
+
+	return float64(len(s.values)) / float64(s.count)
+
+}
+
+type callback struct {
+	series
+	function   func() float64
+	metricType string
+	last       float64
+}
+
+type MetricServiceOption func(*MetricService)
+
+type MetricService struct {
+	mutex            sync.Mutex
+	flushMutex       sync.Mutex
+	network          string
+	address          string
+	conn             net.Conn
+	prefix           string
+	globalTags       []string
+	flushInterval    time.Duration
+	maxPacketSize    int
+	maxSamples       int
+	histogramType    string
+	counters         map[string]*aggregate
+	gauges           map[string]*aggregate
+	histograms       map[string]*samples
+	callbacks        []*callback
+	done             chan struct{}
+	closeOnce        sync.Once
+	flusherWaitGroup sync.WaitGroup
+}
+
+func WithAddress(address string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.address = address
+	}
+}
+
+func WithUnixSocket(path string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.network = "unixgram"
+		m.address = path
+	}
+}
+
+func WithPrefix(prefix string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.prefix = prefix
+	}
+}
+
+func WithGlobalTag(key, value string) MetricServiceOption {
+	return func(m *MetricService) {
+		m.globalTags = append(m.globalTags, key+":"+value)
+	}
+}
+
+func WithFlushInterval(flushInterval time.Duration) MetricServiceOption {
+	return func(m *MetricService) {
+		m.flushInterval = flushInterval
+	}
+}
+
+func WithMaxPacketSize(maxPacketSize int) MetricServiceOption {
+	return func(m *MetricService) {
+		m.maxPacketSize = maxPacketSize
+	}
+}
+
+func WithMaxSamples(maxSamples int) MetricServiceOption {
+	return func(m *MetricService) {
+		m.maxSamples = maxSamples
+	}
+}
+
+func WithDistributions() MetricServiceOption {
+	return func(m *MetricService) {
+		m.histogramType = DistributionType
+	}
+}
+
+func NewMetricService(options ...MetricServiceOption) *MetricService {
+
+	m := &MetricService{
+		network:       "udp",
+		address:       AgentAddressFromEnv(),
+		flushInterval: DefaultFlushInterval,
+		maxPacketSize: DefaultMaxPacketSize,
+		maxSamples:    DefaultMaxSamples,
+		histogramType: HistogramType,
+		counters:      make(map[string]*aggregate),
+		gauges:        make(map[string]*aggregate),
+		histograms:    make(map[string]*samples),
+		done:          make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	conn, err := net.Dial(m.network, m.address)
+	if err != nil {
+		panic(err)
+	}
+
+	m.conn = conn
+
+	if m.flushInterval > 0 {
+		m.flusherWaitGroup.Add(1)
+		go m.flusher()
+	}
+
+	return m
+
+}
+
+func (m *MetricService) Counter(options ...interface{}) metrics.Counter {
+	return NewCounter(m, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) Gauge(options ...interface{}) metrics.Gauge {
+	return NewGauge(m, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) Histogram(options ...interface{}) metrics.Histogram {
+	return NewHistogram(m, InterfaceSliceToMetricOptionSlice(options)...)
+}
+
+func (m *MetricService) GaugeFunc(function func() float64, options ...interface{}) {
+	m.callback(GaugeType, function, InterfaceSliceToMetricOptionSlice(options))
+}
+
+func (m *MetricService) CounterFunc(function func() float64, options ...interface{}) {
+	m.callback(CounterType, function, InterfaceSliceToMetricOptionSlice(options))
+}
+
+func (m *MetricService) Handler() http.Handler {
+	return http.NotFoundHandler()
+}
+
+func (m *MetricService) Push() error {
+	return m.flush()
+}
+
+func (m *MetricService) Close() error {
+
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+
+	m.flusherWaitGroup.Wait()
+
+	err := m.flush()
+
+	if closeErr := m.conn.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+
+}
+
+func (m *MetricService) name(name string) string {
+
+	if len(m.prefix) == 0 {
+		return name
+	}
+
+	return m.prefix + "." + name
+
+}
+
+func (m *MetricService) callback(metricType string, function func() float64, options []metrics.MetricOption) {
+
+	o := metrics.NewMetricOptionSet(options...)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.callbacks = append(m.callbacks, &callback{
+		series:     newSeries(m.name(FullyQualifiedName(o)), MapToTags(o.ConstLabels)),
+		function:   function,
+		metricType: metricType,
+	})
+
+}
+
+func (m *MetricService) evaluate() []string {
+
+	m.mutex.Lock()
+	callbacks := append([]*callback(nil), m.callbacks...)
+	m.mutex.Unlock()
+
+	lines := make([]string, 0, len(callbacks))
+
+	for _, c := range callbacks {
+
+		value := c.function()
+
+		if c.metricType == GaugeType {
+			lines = append(lines, m.line(c.series, value, GaugeType))
+			continue
+		}
+
+		if delta := value - c.last; delta > 0 {
+			lines = append(lines, m.line(c.series, delta, CounterType))
+		}
+
+		c.last = value
+
+	}
+
+	return lines
+
+}
+
+func (m *MetricService) count(s series, value float64) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := s.key()
+
+	counter, ok := m.counters[key]
+	if !ok {
+		counter = &aggregate{series: s}
+		m.counters[key] = counter
+	}
+
+	counter.value += value
+	counter.dirty = true
+
+}
+
+func (m *MetricService) gauge(s series, update func(float64) float64) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := s.key()
+
+	gauge, ok := m.gauges[key]
+	if !ok {
+		gauge = &aggregate{series: s}
+		m.gauges[key] = gauge
+	}
+
+	gauge.value = update(gauge.value)
+	gauge.dirty = true
+
+}
+
+func (m *MetricService) sample(s series, value float64) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := s.key()
+
+	histogram, ok := m.histograms[key]
+	if !ok {
+		histogram = &samples{series: s}
+		m.histograms[key] = histogram
+	}
+
+	histogram.add(value, m.maxSamples)
+
+}
+
+func (m *MetricService) drain() []string {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var lines []string
+
+	for _, counter := range m.counters {
+		if counter.dirty {
+			lines = append(lines, m.line(counter.series, counter.value, CounterType))
+			counter.value = 0
+			counter.dirty = false
+		}
+	}
+
+	for _, gauge := range m.gauges {
+		if gauge.dirty {
+			lines = append(lines, m.line(gauge.series, gauge.value, GaugeType))
+			gauge.dirty = false
+		}
+	}
+
+	for key, histogram := range m.histograms {
+		rate := histogram.rate()
+		for _, value := range histogram.values {
+			lines = append(lines, m.sampledLine(histogram.series, value, m.histogramType, rate))
+		}
+		delete(m.histograms, key)
+	}
+
+	return lines
+
+}
+
+func (m *MetricService) line(s series, value float64, metricType string) string {
+	return m.sampledLine(s, value, metricType, 1)
+}
+
+func (m *MetricService) sampledLine(s series, value float64, metricType string, rate float64) string {
+
+	var b strings.Builder
+
+	b.WriteString(s.name)
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte('|')
+	b.WriteString(metricType)
+
+	if rate < 1 {
+		b.WriteString("|@")
+		b.WriteString(strconv.FormatFloat(rate, 'f', -1, 64))
+	}
+
+	tags := append(append([]string(nil), m.globalTags...), s.tags...)
+	if len(tags) > 0 {
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+
+	return b.String()
+
+}
+
+func (m *MetricService) flush() error {
+
+	m.flushMutex.Lock()
+	defer m.flushMutex.Unlock()
+
+	var packet bytes.Buffer
+	var firstErr error
+
+	write := func() {
+
+		if packet.Len() == 0 {
+			return
+		}
+
+		if _, err := m.conn.Write(packet.Bytes()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		packet.Reset()
+
+	}
+
+	for _, line := range append(m.drain(), m.evaluate()...) {
+
+		if packet.Len() > 0 && packet.Len()+1+len(line) > m.maxPacketSize {
+			write()
+		}
+
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+
+		packet.WriteString(line)
+
+	}
+
+	write()
+
+	return firstErr
+
+}
+
+func (m *MetricService) flusher() {
+
+	defer m.flusherWaitGroup.Done()
+
+	ticker := time.NewTicker(m.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.flush()
+		case <-m.done:
+			return
+		}
+	}
+
+}
+
+type Counter struct {
+	service *MetricService
+	name    string
+	labels  []string
+	tags    []string
+}
+
+func NewCounter(service *MetricService, options ...metrics.MetricOption) *Counter {
+
+	o := metrics.NewMetricOptionSet(options...)
+
+	return &Counter{
+		service: service,
+		name:    service.name(FullyQualifiedName(o)),
+		labels:  o.Labels,
+		tags:    MapToTags(o.ConstLabels),
+	}
+
+}
+
+func (c *Counter) WithLabels(keysAndValues ...string) metrics.Counter {
+	return &Counter{
+		service: c.service,
+		name:    c.name,
+		labels:  c.labels,
+		tags:    append(append([]string(nil), c.tags...), StringSliceToTags(keysAndValues)...),
+	}
+}
+
+func (c *Counter) WithLabelValues(values ...string) metrics.Counter {
+	return &Counter{
+		service: c.service,
+		name:    c.name,
+		labels:  c.labels,
+		tags:    append(append([]string(nil), c.tags...), LabelValuesToTags(c.labels, values)...),
+	}
+}
+
+func (c *Counter) Add(value float64) {
+	c.service.count(newSeries(c.name, c.tags), value)
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) AddWithExemplar(value float64, _ map[string]string) {
+	c.Add(value)
+}
+
+func (c *Counter) AddWithContext(_ context.Context, value float64) {
+	c.Add(value)
+}
+
+type Gauge struct {
+	service *MetricService
+	name    string
+	labels  []string
+	tags    []string
+}
+
+func NewGauge(service *MetricService, options ...metrics.MetricOption) *Gauge {
+
+	o := metrics.NewMetricOptionSet(options...)
+
+	return &Gauge{
+		service: service,
+		name:    service.name(FullyQualifiedName(o)),
+		labels:  o.Labels,
+		tags:    MapToTags(o.ConstLabels),
+	}
+
+}
+
+func (g *Gauge) WithLabels(keysAndValues ...string) metrics.Gauge {
+	return &Gauge{
+		service: g.service,
+		name:    g.name,
+		labels:  g.labels,
+		tags:    append(append([]string(nil), g.tags...), StringSliceToTags(keysAndValues)...),
+	}
+}
+
+func (g *Gauge) WithLabelValues(values ...string) metrics.Gauge {
+	return &Gauge{
+		service: g.service,
+		name:    g.name,
+		labels:  g.labels,
+		tags:    append(append([]string(nil), g.tags...), LabelValuesToTags(g.labels, values)...),
+	}
+}
+
+func (g *Gauge) Add(value float64) {
+	g.service.gauge(newSeries(g.name, g.tags), func(current float64) float64 {
+		return current + value
+	})
+}
+
+func (g *Gauge) Sub(value float64) {
+	g.service.gauge(newSeries(g.name, g.tags), func(current float64) float64 {
+		return current - value
+	})
+}
+
+func (g *Gauge) Set(value float64) {
+	g.service.gauge(newSeries(g.name, g.tags), func(float64) float64 {
+		return value
+	})
+}
+
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.Sub(1)
+}
+
+func (g *Gauge) SetToCurrentTime() {
+	g.Set(float64(time.Now().UnixNano()) / 1e9)
+}
+
+type Histogram struct {
+	service *MetricService
+	name    string
+	labels  []string
+	tags    []string
+}
+
+func NewHistogram(service *MetricService, options ...metrics.MetricOption) *Histogram {
+
+	o := metrics.NewMetricOptionSet(options...)
+
+	return &Histogram{
+		service: service,
+		name:    service.name(FullyQualifiedName(o)),
+		labels:  o.Labels,
+		tags:    MapToTags(o.ConstLabels),
+	}
+
+}
+
+func (h *Histogram) WithLabels(keysAndValues ...string) metrics.Histogram {
+	return &Histogram{
+		service: h.service,
+		name:    h.name,
+		labels:  h.labels,
+		tags:    append(append([]string(nil), h.tags...), StringSliceToTags(keysAndValues)...),
+	}
+}
+
+func (h *Histogram) WithLabelValues(values ...string) metrics.Histogram {
+	return &Histogram{
+		service: h.service,
+		name:    h.name,
+		labels:  h.labels,
+		tags:    append(append([]string(nil), h.tags...), LabelValuesToTags(h.labels, values)...),
+	}
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.service.sample(newSeries(h.name, h.tags), value)
+}
+
+func (h *Histogram) ObserveWithExemplar(value float64, _ map[string]string) {
+	h.Observe(value)
+}
+
+func (h *Histogram) ObserveWithContext(_ context.Context, value float64) {
+	h.Observe(value)
+}
+
+func (h *Histogram) ObserveDuration(duration time.Duration) {
+	h.Observe(duration.Seconds())
+}
+
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (h *Histogram) StartTimer() *metrics.Timer {
+	return metrics.NewTimer(h)
+}
+
+func (h *Histogram) StartTimerWithContext(ctx context.Context) *metrics.Timer {
+	return metrics.NewTimerWithContext(ctx, h)
+}
@@ -0,0 +1,243 @@
+package statsd
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics/prometheus"
+)
+
+func listen(t *testing.T) net.PacketConn {
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+
+}
+
+func newMetricService(t *testing.T, conn net.PacketConn, options ...MetricServiceOption) *MetricService {
+
+	m := NewMetricService(append([]MetricServiceOption{
+		WithAddress(conn.LocalAddr().String()),
+		WithFlushInterval(0),
+	}, options...)...)
+
+	t.Cleanup(func() {
+		m.Close()
+	})
+
+	return m
+
+}
+
+func receive(t *testing.T, conn net.PacketConn) []string {
+
+	var packets []string
+
+	buffer := make([]byte, 65536)
+
+	for {
+
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return packets
+		}
+
+		packets = append(packets, string(buffer[:n]))
+
+	}
+
+}
+
+func lines(packets []string) []string {
+
+	var result []string
+
+	for _, packet := range packets {
+		result = append(result, strings.Split(packet, "\n")...)
+	}
+
+	sort.Strings(result)
+
+	return result
+
+}
+
+func assertLines(t *testing.T, actual []string, expected ...string) {
+
+	t.Helper()
+
+	sort.Strings(expected)
+
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected lines:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+
+}
+
+func TestPushAggregatesCountersAndGauges(t *testing.T) {
+
+	conn := listen(t)
+	m := newMetricService(t, conn, WithPrefix("app"), WithGlobalTag("env", "test"))
+
+	requests := m.Counter(
+		metrics.WithName("requests_total"),
+		metrics.WithLabels([]string{"method"}),
+	)
+
+	requests.WithLabelValues("GET").Add(2)
+	requests.WithLabelValues("GET").Inc()
+	requests.WithLabelValues("POST").Inc()
+
+	queue := m.Gauge(metrics.WithName("queue_size"))
+
+	queue.Set(5)
+	queue.Add(2)
+	queue.Dec()
+
+	if err := m.Push(); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	assertLines(t, lines(receive(t, conn)),
+		"app.requests_total:3|c|#env:test,method:GET",
+		"app.requests_total:1|c|#env:test,method:POST",
+		"app.queue_size:6|g|#env:test",
+	)
+
+	queue.Inc()
+
+	if err := m.Push(); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	assertLines(t, lines(receive(t, conn)), "app.queue_size:7|g|#env:test")
+
+}
+
+func TestPrometheusOptionsAreAccepted(t *testing.T) {
+
+	conn := listen(t)
+	m := newMetricService(t, conn)
+
+	m.Counter(
+		prometheus.WithNamespace("app"),
+		prometheus.WithName("jobs_total"),
+		prometheus.WithConstLabel("queue", "default"),
+	).Inc()
+
+	m.Histogram(
+		prometheus.WithName("job_duration_seconds"),
+		prometheus.WithBuckets([]float64{1}),
+	).Observe(0.5)
+
+	if err := m.Push(); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	assertLines(t, lines(receive(t, conn)),
+		"app.jobs_total:1|c|#queue:default",
+		"job_duration_seconds:0.5|h",
+	)
+
+}
+
+func TestPushSplitsPacketsAtMaxPacketSize(t *testing.T) {
+
+	const maxPacketSize = 64
+
+	conn := listen(t)
+	m := newMetricService(t, conn, WithMaxPacketSize(maxPacketSize))
+
+	gauge := m.Gauge(
+		metrics.WithName("worker_busy"),
+		metrics.WithLabels([]string{"worker"}),
+	)
+
+	var expected []string
+
+	for _, worker := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		gauge.WithLabelValues(worker).Set(1)
+		expected = append(expected, "worker_busy:1|g|#worker:"+worker)
+	}
+
+	if err := m.Push(); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	packets := receive(t, conn)
+
+	if len(packets) < 2 {
+		t.Fatalf("expected lines to be split across packets, got %q", packets)
+	}
+
+	for _, packet := range packets {
+		if len(packet) > maxPacketSize {
+			t.Errorf("packet of %d bytes exceeds %d: %q", len(packet), maxPacketSize, packet)
+		}
+	}
+
+	assertLines(t, lines(packets), expected...)
+
+}
+
+func TestPushReportsSampleRateWhenSamplesAreCapped(t *testing.T) {
+
+	conn := listen(t)
+	m := newMetricService(t, conn, WithMaxSamples(10), WithDistributions())
+
+	histogram := m.Histogram(metrics.WithName("latency_seconds"))
+
+	for i := 0; i < 40; i++ {
+		histogram.Observe(0.1)
+	}
+
+	if err := m.Push(); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+
+	received := lines(receive(t, conn))
+
+	if len(received) != 10 {
+		t.Fatalf("expected 10 sampled lines, got %d: %q", len(received), received)
+	}
+
+	for _, line := range received {
+		if line != "latency_seconds:0.1|d|@0.25" {
+			t.Errorf("unexpected sampled line %q", line)
+		}
+	}
+
+}
+
+func TestCloseFlushesPendingMetrics(t *testing.T) {
+
+	conn := listen(t)
+
+	m := NewMetricService(
+		WithAddress(conn.LocalAddr().String()),
+		WithFlushInterval(time.Hour),
+	)
+
+	m.Counter(metrics.WithName("shutdowns_total")).Inc()
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	assertLines(t, lines(receive(t, conn)), "shutdowns_total:1|c")
+
+}
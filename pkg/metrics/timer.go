@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Timer struct {
+	histogram Histogram
+	ctx       context.Context
+	start     time.Time
+	once      sync.Once
+	elapsed   time.Duration
+}
+
+func NewTimer(histogram Histogram) *Timer {
+	return NewTimerWithContext(context.Background(), histogram)
+}
+
+func NewTimerWithContext(ctx context.Context, histogram Histogram) *Timer {
+	return &Timer{
+		histogram: histogram,
+		ctx:       ctx,
+		start:     time.Now(),
+	}
+}
+
+func (t *Timer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+func (t *Timer) Stop() time.Duration {
+
+	t.once.Do(func() {
+		t.elapsed = time.Since(t.start)
+		t.histogram.ObserveWithContext(t.ctx, t.elapsed.Seconds())
+	})
+
+	return t.elapsed
+
+}
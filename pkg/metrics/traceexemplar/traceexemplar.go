@@ -0,0 +1,36 @@
+package traceexemplar
+
+import (
+	"context"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TraceIDExemplarLabel = "trace_id"
+	SpanIDExemplarLabel  = "span_id"
+)
+
+func TraceExemplar(ctx context.Context) map[string]string {
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return nil
+	}
+
+	exemplar := map[string]string{
+		TraceIDExemplarLabel: spanContext.TraceID().String(),
+	}
+
+	if spanContext.HasSpanID() {
+		exemplar[SpanIDExemplarLabel] = spanContext.SpanID().String()
+	}
+
+	return exemplar
+
+}
+
+func Enable() {
+	metrics.SetExemplarFunc(TraceExemplar)
+}
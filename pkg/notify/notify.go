@@ -0,0 +1,9 @@
+package notify
+
+type NotifierService interface {
+	Ready() error
+	Stopping() error
+	Status(string) error
+	StartWatchdog(func() error)
+	Close() error
+}
@@ -0,0 +1,192 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
+)
+
+const (
+	ReadyState     = "READY=1"
+	StoppingState  = "STOPPING=1"
+	ReloadingState = "RELOADING=1"
+	WatchdogState  = "WATCHDOG=1"
+)
+
+type NotifierServiceOption func(*NotifierService)
+
+type NotifierService struct {
+	socket           string
+	watchdogInterval time.Duration
+	logging          logging.LoggingService
+	stoppingOnce     sync.Once
+	stop             chan struct{}
+	stopOnce         sync.Once
+	wg               sync.WaitGroup
+}
+
+func WithSocket(socket string) NotifierServiceOption {
+	return func(n *NotifierService) {
+		n.socket = socket
+	}
+}
+
+func WithWatchdogInterval(interval time.Duration) NotifierServiceOption {
+	return func(n *NotifierService) {
+		n.watchdogInterval = interval
+	}
+}
+
+func WithLogging(loggingService logging.LoggingService) NotifierServiceOption {
+	return func(n *NotifierService) {
+		n.logging = loggingService
+	}
+}
+
+func NewNotifierService(options ...NotifierServiceOption) *NotifierService {
+
+	n := &NotifierService{
+		socket:           os.Getenv("NOTIFY_SOCKET"),
+		watchdogInterval: watchdogIntervalFromEnv(),
+		stop:             make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(n)
+	}
+
+	return n
+
+}
+
+func watchdogIntervalFromEnv() time.Duration {
+
+	if pid := os.Getenv("WATCHDOG_PID"); len(pid) > 0 && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2
+
+}
+
+func (n *NotifierService) Enabled() bool {
+	return len(n.socket) > 0
+}
+
+func (n *NotifierService) Notify(states ...string) error {
+
+	if !n.Enabled() {
+		return nil
+	}
+
+	socket := n.socket
+
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(strings.Join(states, "\n")))
+
+	return err
+
+}
+
+func (n *NotifierService) Ready() error {
+	return n.Notify(ReadyState)
+}
+
+func (n *NotifierService) Stopping() error {
+
+	var err error
+
+	n.stoppingOnce.Do(func() {
+		err = n.Notify(StoppingState)
+	})
+
+	return err
+
+}
+
+func (n *NotifierService) Reloading() error {
+	return n.Notify(ReloadingState)
+}
+
+func (n *NotifierService) Status(status string) error {
+	return n.Notify("STATUS=" + status)
+}
+
+func (n *NotifierService) Watchdog() error {
+	return n.Notify(WatchdogState)
+}
+
+func (n *NotifierService) StartWatchdog(check func() error) {
+
+	if !n.Enabled() || n.watchdogInterval <= 0 {
+		return
+	}
+
+	n.wg.Add(1)
+
+	go func() {
+
+		defer n.wg.Done()
+
+		ticker := time.NewTicker(n.watchdogInterval)
+		defer ticker.Stop()
+
+		for {
+
+			select {
+			case <-n.stop:
+				return
+			case <-ticker.C:
+
+				if check != nil {
+					if err := check(); err != nil {
+						if n.logging != nil {
+							n.logging.Warn("skipping watchdog notification", "error", err.Error())
+						}
+						continue
+					}
+				}
+
+				if err := n.Watchdog(); err != nil && n.logging != nil {
+					n.logging.Warn("failed to send watchdog notification", "error", err.Error())
+				}
+
+			}
+
+		}
+
+	}()
+
+}
+
+func (n *NotifierService) Close() error {
+
+	n.stopOnce.Do(func() {
+		close(n.stop)
+	})
+
+	n.wg.Wait()
+
+	return nil
+
+}
@@ -0,0 +1,151 @@
+package systemd
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func listen(t *testing.T) (*net.UnixConn, string) {
+
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", socket, err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn, socket
+
+}
+
+func receive(t *testing.T, conn *net.UnixConn) string {
+
+	buffer := make([]byte, 4096)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	n, err := conn.Read(buffer)
+	if err != nil {
+		t.Fatalf("failed to receive notification: %v", err)
+	}
+
+	return string(buffer[:n])
+
+}
+
+func TestNotifySendsStates(t *testing.T) {
+
+	conn, socket := listen(t)
+
+	n := NewNotifierService(WithSocket(socket))
+
+	if !n.Enabled() {
+		t.Fatal("expected notifier to be enabled")
+	}
+
+	if err := n.Ready(); err != nil {
+		t.Fatalf("ready failed: %v", err)
+	}
+
+	if state := receive(t, conn); state != ReadyState {
+		t.Errorf("expected %q, got %q", ReadyState, state)
+	}
+
+	if err := n.Status("serving"); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+
+	if state := receive(t, conn); state != "STATUS=serving" {
+		t.Errorf("expected %q, got %q", "STATUS=serving", state)
+	}
+
+	if err := n.Stopping(); err != nil {
+		t.Fatalf("stopping failed: %v", err)
+	}
+
+	if state := receive(t, conn); state != StoppingState {
+		t.Errorf("expected %q, got %q", StoppingState, state)
+	}
+
+	if err := n.Stopping(); err != nil {
+		t.Fatalf("second stopping failed: %v", err)
+	}
+
+	if err := n.Notify(ReadyState, "STATUS=again"); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	if state := receive(t, conn); state != ReadyState+"\nSTATUS=again" {
+		t.Errorf("expected stopping to be sent once, got %q", state)
+	}
+
+}
+
+func TestWatchdogSendsWhileCheckPasses(t *testing.T) {
+
+	conn, socket := listen(t)
+
+	n := NewNotifierService(
+		WithSocket(socket),
+		WithWatchdogInterval(10*time.Millisecond),
+	)
+
+	n.StartWatchdog(func() error {
+		return nil
+	})
+
+	defer n.Close()
+
+	for i := 0; i < 2; i++ {
+		if state := receive(t, conn); state != WatchdogState {
+			t.Errorf("expected %q, got %q", WatchdogState, state)
+		}
+	}
+
+}
+
+func TestWatchdogSkipsWhileCheckFails(t *testing.T) {
+
+	conn, socket := listen(t)
+
+	n := NewNotifierService(
+		WithSocket(socket),
+		WithWatchdogInterval(10*time.Millisecond),
+	)
+
+	n.StartWatchdog(func() error {
+		return errors.New("unhealthy")
+	})
+
+	time.Sleep(50 * time.Millisecond)
+
+	n.Close()
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+
+	if _, err := conn.Read(make([]byte, 4096)); err == nil {
+		t.Error("expected no watchdog notification while the check fails")
+	}
+
+}
+
+func TestDisabledWithoutSocket(t *testing.T) {
+
+	n := NewNotifierService(WithSocket(""))
+
+	if n.Enabled() {
+		t.Fatal("expected notifier to be disabled")
+	}
+
+	if err := n.Ready(); err != nil {
+		t.Errorf("expected no error when disabled, got %v", err)
+	}
+
+}
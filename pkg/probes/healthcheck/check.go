@@ -0,0 +1,308 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/probes"
+	"github.com/heptiolabs/healthcheck"
+)
+
+type CheckOption func(*checkState)
+
+func WithFailureThreshold(threshold int) CheckOption {
+	return func(c *checkState) {
+		c.failureThreshold = threshold
+	}
+}
+
+func WithSuccessThreshold(threshold int) CheckOption {
+	return func(c *checkState) {
+		c.successThreshold = threshold
+	}
+}
+
+func WithStartupGracePeriod(gracePeriod time.Duration) CheckOption {
+	return func(c *checkState) {
+		c.gracePeriod = gracePeriod
+	}
+}
+
+type checkState struct {
+	mutex                sync.Mutex
+	name                 string
+	check                healthcheck.Check
+	err                  error
+	lastRun              time.Time
+	lastSuccess          time.Time
+	duration             time.Duration
+	consecutiveFailures  int
+	consecutiveSuccesses int
+	failureThreshold     int
+	successThreshold     int
+	gracePeriod          time.Duration
+	registered           time.Time
+	healthy              bool
+}
+
+func (c *checkState) run(record bool) probes.CheckResult {
+
+	start := time.Now()
+	err := c.check()
+	duration := time.Since(start)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !record {
+		return c.observe(start, duration, err)
+	}
+
+	c.err = err
+	c.lastRun = start
+	c.duration = duration
+
+	if err != nil {
+		c.consecutiveFailures++
+		c.consecutiveSuccesses = 0
+	} else {
+		c.consecutiveFailures = 0
+		c.consecutiveSuccesses++
+		c.lastSuccess = start
+	}
+
+	switch {
+	case c.inGracePeriod(start):
+		c.healthy = true
+	case c.healthy && c.consecutiveFailures >= c.failureThreshold:
+		c.healthy = false
+	case !c.healthy && c.consecutiveSuccesses >= c.successThreshold:
+		c.healthy = true
+	}
+
+	return c.result()
+
+}
+
+func (c *checkState) observe(start time.Time, duration time.Duration, err error) probes.CheckResult {
+
+	result := c.result()
+
+	result.Status = probes.PassStatus
+	result.Error = ""
+	result.LastRun = start
+	result.Duration = duration.String()
+	result.InGracePeriod = c.inGracePeriod(start)
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	if err != nil && !result.InGracePeriod {
+		result.Status = probes.FailStatus
+	}
+
+	return result
+
+}
+
+func (c *checkState) inGracePeriod(now time.Time) bool {
+	return c.gracePeriod > 0 && now.Sub(c.registered) < c.gracePeriod
+}
+
+func (c *checkState) result() probes.CheckResult {
+
+	result := probes.CheckResult{
+		Name:                 c.name,
+		Status:               probes.PassStatus,
+		LastRun:              c.lastRun,
+		Duration:             c.duration.String(),
+		ConsecutiveFailures:  c.consecutiveFailures,
+		ConsecutiveSuccesses: c.consecutiveSuccesses,
+		InGracePeriod:        c.inGracePeriod(c.lastRun),
+	}
+
+	if !c.healthy {
+		result.Status = probes.FailStatus
+	}
+
+	if c.err != nil {
+		result.Error = c.err.Error()
+	}
+
+	if !c.lastSuccess.IsZero() {
+		lastSuccess := c.lastSuccess
+		result.LastSuccess = &lastSuccess
+	}
+
+	return result
+
+}
+
+type probeFlight struct {
+	done   chan struct{}
+	record bool
+	result probes.ProbeResult
+}
+
+type probeCache struct {
+	mutex    sync.Mutex
+	result   probes.ProbeResult
+	expires  time.Time
+	recorded bool
+	flight   *probeFlight
+}
+
+func (p *ProbeService) addLivenessCheck(name string, check healthcheck.Check, options ...CheckOption) {
+	p.liveness = addCheck(p.liveness, name, check, true, options...)
+	p.readinessFromLiveness = addCheck(p.readinessFromLiveness, name, check, false, options...)
+}
+
+func (p *ProbeService) addReadinessCheck(name string, check healthcheck.Check, options ...CheckOption) {
+	p.readiness = addCheck(p.readiness, name, check, false, options...)
+}
+
+func addCheck(checks []*checkState, name string, check healthcheck.Check, healthy bool, options ...CheckOption) []*checkState {
+
+	state := &checkState{
+		name:             name,
+		check:            check,
+		failureThreshold: 1,
+		successThreshold: 1,
+		registered:       time.Now(),
+		healthy:          healthy,
+	}
+
+	for _, option := range options {
+		option(state)
+	}
+
+	if state.failureThreshold < 1 {
+		state.failureThreshold = 1
+	}
+
+	if state.successThreshold < 1 {
+		state.successThreshold = 1
+	}
+
+	for i, existing := range checks {
+		if existing.name == name {
+			checks[i] = state
+			return checks
+		}
+	}
+
+	return append(checks, state)
+
+}
+
+func (p *ProbeService) checks(probe string) []*checkState {
+
+	if probe == probes.LivenessProbe {
+		return p.liveness
+	}
+
+	return append(append([]*checkState(nil), p.readinessFromLiveness...), p.readiness...)
+
+}
+
+func (p *ProbeService) Status(probe string) probes.ProbeResult {
+	return p.evaluate(probe, false)
+}
+
+func (p *ProbeService) evaluate(probe string, record bool) probes.ProbeResult {
+
+	cache := p.cache[probe]
+
+	cache.mutex.Lock()
+
+	for {
+
+		if !record && cache.recorded {
+			result := cache.result
+			cache.mutex.Unlock()
+			return result
+		}
+
+		if time.Now().Before(cache.expires) && (cache.recorded || !record) {
+			result := cache.result
+			cache.mutex.Unlock()
+			return result
+		}
+
+		flight := cache.flight
+		if flight == nil {
+			break
+		}
+
+		cache.mutex.Unlock()
+		<-flight.done
+
+		if flight.record || !record {
+			return flight.result
+		}
+
+		cache.mutex.Lock()
+
+	}
+
+	flight := &probeFlight{
+		done:   make(chan struct{}),
+		record: record,
+	}
+
+	cache.flight = flight
+	cache.mutex.Unlock()
+
+	flight.result = p.runChecks(probe, record)
+
+	cache.mutex.Lock()
+
+	cache.result = flight.result
+	cache.expires = time.Now().Add(p.cacheTTL)
+	cache.recorded = cache.recorded || record
+	cache.flight = nil
+
+	cache.mutex.Unlock()
+
+	close(flight.done)
+
+	return flight.result
+
+}
+
+func (p *ProbeService) runChecks(probe string, record bool) probes.ProbeResult {
+
+	checks := p.checks(probe)
+
+	result := probes.ProbeResult{
+		Probe:     probe,
+		Status:    probes.PassStatus,
+		Timestamp: time.Now(),
+		Checks:    make([]probes.CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+
+		wg.Add(1)
+
+		go func(i int, check *checkState) {
+			defer wg.Done()
+			result.Checks[i] = check.run(record)
+		}(i, check)
+
+	}
+
+	wg.Wait()
+
+	for _, check := range result.Checks {
+		if check.Status != probes.PassStatus {
+			result.Status = probes.FailStatus
+		}
+	}
+
+	return result
+
+}
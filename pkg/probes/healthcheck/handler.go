@@ -0,0 +1,76 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/definancialbr/golang-container-kit/pkg/probes"
+)
+
+func (p *ProbeService) handler(probe string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		result := p.evaluate(probe, true)
+
+		status := http.StatusOK
+		if !result.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+
+		if wantsText(r) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(status)
+			writeText(w, result)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+
+	}
+}
+
+func wantsText(r *http.Request) bool {
+
+	if format := r.URL.Query().Get("format"); len(format) > 0 {
+		return format == "text"
+	}
+
+	accept := r.Header.Get("Accept")
+
+	return strings.Contains(accept, "text/plain") && !strings.Contains(accept, "application/json")
+
+}
+
+func writeText(w http.ResponseWriter, result probes.ProbeResult) {
+
+	fmt.Fprintf(w, "%s: %s\n", result.Probe, result.Status)
+
+	for _, check := range result.Checks {
+
+		if check.Status == probes.PassStatus {
+			fmt.Fprintf(w, "[+] %s ok (%s)\n", check.Name, check.Duration)
+			continue
+		}
+
+		fmt.Fprintf(w, "[-] %s failed: %s (%s, %d consecutive failures)\n",
+			check.Name,
+			check.Error,
+			check.Duration,
+			check.ConsecutiveFailures,
+		)
+
+	}
+
+}
@@ -6,127 +6,146 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/definancialbr/golang-container-kit/pkg/probes"
 	"github.com/heptiolabs/healthcheck"
 )
 
+var (
+	DefaultResponseCacheTTL = time.Second
+)
+
 type ProbeServiceOption func(*ProbeService)
 
 type ProbeService struct {
-	health healthcheck.Handler
+	liveness              []*checkState
+	readiness             []*checkState
+	readinessFromLiveness []*checkState
+	cacheTTL              time.Duration
+	cache                 map[string]*probeCache
+}
+
+func WithResponseCache(ttl time.Duration) ProbeServiceOption {
+	return func(p *ProbeService) {
+		p.cacheTTL = ttl
+	}
 }
 
-func WithCheckForLiveness(name string, check func() error) ProbeServiceOption {
+func WithCheckForLiveness(name string, check func() error, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, check)
+		p.addLivenessCheck(name, check, options...)
 	}
 }
 
-func WithCheckForReadiness(name string, check func() error) ProbeServiceOption {
+func WithCheckForReadiness(name string, check func() error, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, check)
+		p.addReadinessCheck(name, check, options...)
 	}
 }
 
-func WithGoroutineCountCheckForLiveness(name string, threshold int) ProbeServiceOption {
+func WithGoroutineCountCheckForLiveness(name string, threshold int, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.GoroutineCountCheck(threshold))
+		p.addLivenessCheck(name, healthcheck.GoroutineCountCheck(threshold), options...)
 	}
 }
 
-func WithGoroutineCountCheckForReadiness(name string, threshold int) ProbeServiceOption {
+func WithGoroutineCountCheckForReadiness(name string, threshold int, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.GoroutineCountCheck(threshold))
+		p.addReadinessCheck(name, healthcheck.GoroutineCountCheck(threshold), options...)
 	}
 }
 
-func WithHTTPGetCheckForLiveness(name string, url string, timeout time.Duration) ProbeServiceOption {
+func WithHTTPGetCheckForLiveness(name string, url string, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.HTTPGetCheck(url, timeout))
+		p.addLivenessCheck(name, healthcheck.HTTPGetCheck(url, timeout), options...)
 	}
 }
 
-func WithHTTPGetCheckForReadiness(name string, url string, timeout time.Duration) ProbeServiceOption {
+func WithHTTPGetCheckForReadiness(name string, url string, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.HTTPGetCheck(url, timeout))
+		p.addReadinessCheck(name, healthcheck.HTTPGetCheck(url, timeout), options...)
 	}
 }
 
-func WithDNSResolveCheckForLiveness(name string, host string, timeout time.Duration) ProbeServiceOption {
+func WithDNSResolveCheckForLiveness(name string, host string, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.DNSResolveCheck(host, timeout))
+		p.addLivenessCheck(name, healthcheck.DNSResolveCheck(host, timeout), options...)
 	}
 }
 
-func WithDNSResolveCheckForReadiness(name string, host string, timeout time.Duration) ProbeServiceOption {
+func WithDNSResolveCheckForReadiness(name string, host string, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.DNSResolveCheck(host, timeout))
+		p.addReadinessCheck(name, healthcheck.DNSResolveCheck(host, timeout), options...)
 	}
 }
 
-func WithTCPDialCheckForLiveness(name string, addr string, timeout time.Duration) ProbeServiceOption {
+func WithTCPDialCheckForLiveness(name string, addr string, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.TCPDialCheck(addr, timeout))
+		p.addLivenessCheck(name, healthcheck.TCPDialCheck(addr, timeout), options...)
 	}
 }
 
-func WithTCPDialCheckForReadiness(name string, addr string, timeout time.Duration) ProbeServiceOption {
+func WithTCPDialCheckForReadiness(name string, addr string, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.TCPDialCheck(addr, timeout))
+		p.addReadinessCheck(name, healthcheck.TCPDialCheck(addr, timeout), options...)
 	}
 }
 
-func WithDatabasePingCheckForLiveness(name string, database *sql.DB, timeout time.Duration) ProbeServiceOption {
+func WithDatabasePingCheckForLiveness(name string, database *sql.DB, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.DatabasePingCheck(database, timeout))
+		p.addLivenessCheck(name, healthcheck.DatabasePingCheck(database, timeout), options...)
 	}
 }
 
-func WithDatabasePingCheckForReadiness(name string, database *sql.DB, timeout time.Duration) ProbeServiceOption {
+func WithDatabasePingCheckForReadiness(name string, database *sql.DB, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.DatabasePingCheck(database, timeout))
+		p.addReadinessCheck(name, healthcheck.DatabasePingCheck(database, timeout), options...)
 	}
 }
 
-func WithTimeoutCheckForLiveness(name string, check healthcheck.Check, timeout time.Duration) ProbeServiceOption {
+func WithTimeoutCheckForLiveness(name string, check healthcheck.Check, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.Timeout(check, timeout))
+		p.addLivenessCheck(name, healthcheck.Timeout(check, timeout), options...)
 	}
 }
 
-func WithTimeoutCheckForReadiness(name string, check healthcheck.Check, timeout time.Duration) ProbeServiceOption {
+func WithTimeoutCheckForReadiness(name string, check healthcheck.Check, timeout time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.Timeout(check, timeout))
+		p.addReadinessCheck(name, healthcheck.Timeout(check, timeout), options...)
 	}
 }
 
-func WithAsyncCheckForLiveness(name string, check healthcheck.Check, interval time.Duration) ProbeServiceOption {
+func WithAsyncCheckForLiveness(name string, check healthcheck.Check, interval time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.Async(check, interval))
+		p.addLivenessCheck(name, healthcheck.Async(check, interval), options...)
 	}
 }
 
-func WithAsyncCheckForReadiness(name string, check healthcheck.Check, interval time.Duration) ProbeServiceOption {
+func WithAsyncCheckForReadiness(name string, check healthcheck.Check, interval time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.Async(check, interval))
+		p.addReadinessCheck(name, healthcheck.Async(check, interval), options...)
 	}
 }
 
-func WithAsyncWithContextCheckForLiveness(name string, ctx context.Context, check healthcheck.Check, interval time.Duration) ProbeServiceOption {
+func WithAsyncWithContextCheckForLiveness(name string, ctx context.Context, check healthcheck.Check, interval time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddLivenessCheck(name, healthcheck.AsyncWithContext(ctx, check, interval))
+		p.addLivenessCheck(name, healthcheck.AsyncWithContext(ctx, check, interval), options...)
 	}
 }
 
-func WithAsyncWithContextCheckForReadiness(name string, ctx context.Context, check healthcheck.Check, interval time.Duration) ProbeServiceOption {
+func WithAsyncWithContextCheckForReadiness(name string, ctx context.Context, check healthcheck.Check, interval time.Duration, options ...CheckOption) ProbeServiceOption {
 	return func(p *ProbeService) {
-		p.health.AddReadinessCheck(name, healthcheck.AsyncWithContext(ctx, check, interval))
+		p.addReadinessCheck(name, healthcheck.AsyncWithContext(ctx, check, interval), options...)
 	}
 }
 
 func NewProbeService(options ...ProbeServiceOption) *ProbeService {
 
 	p := &ProbeService{
-		health: healthcheck.NewHandler(),
+		cacheTTL: DefaultResponseCacheTTL,
+		cache: map[string]*probeCache{
+			probes.LivenessProbe:  {},
+			probes.ReadinessProbe: {},
+		},
 	}
 
 	for _, option := range options {
@@ -138,9 +157,9 @@ func NewProbeService(options ...ProbeServiceOption) *ProbeService {
 }
 
 func (p *ProbeService) LivenessHandler() http.HandlerFunc {
-	return p.health.LiveEndpoint
+	return p.handler(probes.LivenessProbe)
 }
 
 func (p *ProbeService) ReadinessHandler() http.HandlerFunc {
-	return p.health.ReadyEndpoint
+	return p.handler(probes.ReadinessProbe)
 }
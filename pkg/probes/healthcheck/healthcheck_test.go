@@ -0,0 +1,239 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/probes"
+)
+
+func slowCheck(runs *int32, delay time.Duration) func() error {
+	return func() error {
+		atomic.AddInt32(runs, 1)
+		time.Sleep(delay)
+		return nil
+	}
+}
+
+func TestConcurrentStatusRunsChecksOnce(t *testing.T) {
+
+	var runs int32
+
+	p := NewProbeService(
+		WithResponseCache(5*time.Second),
+		WithCheckForReadiness("slow", slowCheck(&runs, 100*time.Millisecond)),
+	)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			p.Status(probes.ReadinessProbe)
+		}()
+
+	}
+
+	wg.Wait()
+
+	if runs != 1 {
+		t.Errorf("expected the check to run once, ran %d times", runs)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected concurrent callers to share one run, took %s", elapsed)
+	}
+
+}
+
+func TestConcurrentStatusCoalescesWithoutCache(t *testing.T) {
+
+	var runs int32
+
+	p := NewProbeService(
+		WithResponseCache(0),
+		WithCheckForReadiness("slow", slowCheck(&runs, 100*time.Millisecond)),
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			p.Status(probes.ReadinessProbe)
+		}()
+
+	}
+
+	wg.Wait()
+
+	if runs >= 10 {
+		t.Errorf("expected in-flight runs to be shared, ran %d times", runs)
+	}
+
+}
+
+func TestResponseCacheIsEnabledByDefault(t *testing.T) {
+
+	var runs int32
+
+	p := NewProbeService(
+		WithCheckForLiveness("fast", slowCheck(&runs, 0)),
+	)
+
+	for i := 0; i < 3; i++ {
+		p.Status(probes.LivenessProbe)
+	}
+
+	if runs != 1 {
+		t.Errorf("expected cached liveness results, ran %d times", runs)
+	}
+
+}
+
+func TestLivenessIsNotBlockedBySlowReadiness(t *testing.T) {
+
+	var runs int32
+
+	p := NewProbeService(
+		WithCheckForReadiness("slow", slowCheck(&runs, 500*time.Millisecond)),
+		WithCheckForLiveness("fast", func() error {
+			return nil
+		}),
+	)
+
+	go p.Status(probes.ReadinessProbe)
+
+	time.Sleep(10 * time.Millisecond)
+
+	start := time.Now()
+
+	if result := p.Status(probes.LivenessProbe); !result.Healthy() {
+		t.Errorf("expected liveness to pass, got %+v", result)
+	}
+
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("expected liveness to answer while readiness runs, took %s", elapsed)
+	}
+
+}
+
+func probe(handler http.HandlerFunc) int {
+
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return recorder.Code
+
+}
+
+func failing() error {
+	return errors.New("unavailable")
+}
+
+func TestReadinessFailsUntilFirstSuccess(t *testing.T) {
+
+	healthy := false
+
+	p := NewProbeService(
+		WithResponseCache(0),
+		WithCheckForReadiness("db", func() error {
+			if !healthy {
+				return failing()
+			}
+			return nil
+		}, WithFailureThreshold(3), WithSuccessThreshold(2)),
+	)
+
+	for i := 0; i < 3; i++ {
+		if code := probe(p.ReadinessHandler()); code != http.StatusServiceUnavailable {
+			t.Fatalf("probe %d: expected readiness to fail before any success, got %d", i, code)
+		}
+	}
+
+	healthy = true
+
+	expected := []int{http.StatusServiceUnavailable, http.StatusOK}
+
+	for i, status := range expected {
+		if code := probe(p.ReadinessHandler()); code != status {
+			t.Errorf("success %d: expected %d, got %d", i, status, code)
+		}
+	}
+
+}
+
+func TestReadinessPassesDuringGracePeriod(t *testing.T) {
+
+	p := NewProbeService(
+		WithResponseCache(0),
+		WithCheckForReadiness("db", failing, WithStartupGracePeriod(time.Minute)),
+	)
+
+	if code := probe(p.ReadinessHandler()); code != http.StatusOK {
+		t.Errorf("expected readiness to pass during the grace period, got %d", code)
+	}
+
+}
+
+func TestStatusDoesNotAdvanceThresholds(t *testing.T) {
+
+	p := NewProbeService(
+		WithResponseCache(0),
+		WithCheckForLiveness("deadlock", failing, WithFailureThreshold(2)),
+	)
+
+	for i := 0; i < 5; i++ {
+		p.Status(probes.LivenessProbe)
+	}
+
+	if code := probe(p.LivenessHandler()); code != http.StatusOK {
+		t.Fatalf("expected the first probe to stay within the failure threshold, got %d", code)
+	}
+
+	result := p.Status(probes.LivenessProbe)
+
+	if !result.Healthy() || result.Checks[0].ConsecutiveFailures != 1 {
+		t.Errorf("expected status to report the last probe result, got %+v", result)
+	}
+
+	if code := probe(p.LivenessHandler()); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the second probe to reach the failure threshold, got %d", code)
+	}
+
+	if p.Status(probes.LivenessProbe).Healthy() {
+		t.Error("expected status to follow the failed probe")
+	}
+
+}
+
+func TestStatusReportsRawResultsBeforeAnyProbe(t *testing.T) {
+
+	p := NewProbeService(
+		WithResponseCache(0),
+		WithCheckForLiveness("deadlock", failing, WithFailureThreshold(3)),
+	)
+
+	if result := p.Status(probes.LivenessProbe); result.Healthy() || result.Checks[0].Error != "unavailable" {
+		t.Errorf("expected status to report the failing check, got %+v", result)
+	}
+
+	if code := probe(p.LivenessHandler()); code != http.StatusOK {
+		t.Errorf("expected status calls not to count towards the failure threshold, got %d", code)
+	}
+
+}
@@ -1,8 +1,70 @@
 package probes
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	LivenessProbe  = "liveness"
+	ReadinessProbe = "readiness"
+
+	PassStatus = "pass"
+	FailStatus = "fail"
+)
 
 type ProbeService interface {
 	LivenessHandler() http.HandlerFunc
 	ReadinessHandler() http.HandlerFunc
+	Status(string) ProbeResult
+}
+
+type CheckResult struct {
+	Name                 string     `json:"name"`
+	Status               string     `json:"status"`
+	Error                string     `json:"error,omitempty"`
+	LastRun              time.Time  `json:"lastRun"`
+	LastSuccess          *time.Time `json:"lastSuccess,omitempty"`
+	Duration             string     `json:"duration"`
+	ConsecutiveFailures  int        `json:"consecutiveFailures"`
+	ConsecutiveSuccesses int        `json:"consecutiveSuccesses"`
+	InGracePeriod        bool       `json:"inGracePeriod,omitempty"`
+}
+
+type ProbeResult struct {
+	Probe     string        `json:"probe"`
+	Status    string        `json:"status"`
+	Timestamp time.Time     `json:"timestamp"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+func (r ProbeResult) Healthy() bool {
+	return r.Status == PassStatus
+}
+
+func CheckLiveness(p ProbeService) error {
+	return check(p, LivenessProbe)
+}
+
+func CheckReadiness(p ProbeService) error {
+	return check(p, ReadinessProbe)
+}
+
+func check(p ProbeService, probe string) error {
+
+	result := p.Status(probe)
+
+	if result.Healthy() {
+		return nil
+	}
+
+	for _, check := range result.Checks {
+		if check.Status != PassStatus {
+			return fmt.Errorf("%s probe failed: %s: %s", probe, check.Name, check.Error)
+		}
+	}
+
+	return fmt.Errorf("%s probe failed", probe)
+
 }
@@ -0,0 +1,250 @@
+package signaler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/configuration"
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
+	"github.com/definancialbr/golang-container-kit/pkg/probes"
+)
+
+var (
+	DefaultRedactedKeys = []string{"password", "secret", "token", "key", "credential", "auth"}
+	RedactedValue       = "[REDACTED]"
+)
+
+type DiagnosticsOption func(*Diagnostics)
+
+type Diagnostics struct {
+	configuration configuration.ConfigurationService
+	probes        probes.ProbeService
+	logging       logging.LoggingService
+	directory     string
+	redactedKeys  []string
+	started       time.Time
+}
+
+func WithDiagnosticsConfiguration(configurationService configuration.ConfigurationService) DiagnosticsOption {
+	return func(d *Diagnostics) {
+		d.configuration = configurationService
+	}
+}
+
+func WithDiagnosticsProbes(probeService probes.ProbeService) DiagnosticsOption {
+	return func(d *Diagnostics) {
+		d.probes = probeService
+	}
+}
+
+func WithDiagnosticsLogging(loggingService logging.LoggingService) DiagnosticsOption {
+	return func(d *Diagnostics) {
+		d.logging = loggingService
+	}
+}
+
+func WithDiagnosticsDirectory(directory string) DiagnosticsOption {
+	return func(d *Diagnostics) {
+		d.directory = directory
+	}
+}
+
+func WithRedactedKeys(keys ...string) DiagnosticsOption {
+	return func(d *Diagnostics) {
+		d.redactedKeys = append(d.redactedKeys, keys...)
+	}
+}
+
+func NewDiagnostics(options ...DiagnosticsOption) *Diagnostics {
+
+	d := &Diagnostics{
+		redactedKeys: append([]string(nil), DefaultRedactedKeys...),
+		started:      time.Now(),
+	}
+
+	for _, option := range options {
+		option(d)
+	}
+
+	return d
+
+}
+
+func WithDiagnostics(sig os.Signal, diagnostics *Diagnostics) SignalerOption {
+	return WithSignalHandler(sig, diagnostics.Handler(), WithHandlerName("diagnostics"), WithHandlerAsync())
+}
+
+func (d *Diagnostics) Handler() SignalHandler {
+	return func(func()) error {
+		return d.Dump()
+	}
+}
+
+func (d *Diagnostics) Dump() error {
+
+	if len(d.directory) > 0 {
+		return d.dumpToDirectory()
+	}
+
+	if d.logging == nil {
+		return fmt.Errorf("diagnostics require a logging service or a directory")
+	}
+
+	var heap bytes.Buffer
+
+	if err := pprof.Lookup("heap").WriteTo(&heap, 1); err != nil {
+		return err
+	}
+
+	d.logging.Info("diagnostic dump",
+		"runtime", d.RuntimeStats(),
+		"configuration", d.Configuration(),
+		"probes", d.ProbeStatus(),
+		"goroutines", GoroutineDump(),
+		"heap", heap.String(),
+	)
+
+	return nil
+
+}
+
+func (d *Diagnostics) dumpToDirectory() error {
+
+	directory := filepath.Join(d.directory, "diagnostics-"+time.Now().UTC().Format("20060102T150405.000000000Z"))
+
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return err
+	}
+
+	var heap bytes.Buffer
+
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		return err
+	}
+
+	files := map[string]interface{}{
+		"runtime.json":       d.RuntimeStats(),
+		"configuration.json": d.Configuration(),
+		"probes.json":        d.ProbeStatus(),
+	}
+
+	for name, content := range files {
+
+		data, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(directory, name), data, 0o600); err != nil {
+			return err
+		}
+
+	}
+
+	if err := os.WriteFile(filepath.Join(directory, "goroutines.txt"), []byte(GoroutineDump()), 0o600); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(directory, "heap.pprof"), heap.Bytes(), 0o600); err != nil {
+		return err
+	}
+
+	if d.logging != nil {
+		d.logging.Info("diagnostic dump written", "directory", directory)
+	}
+
+	return nil
+
+}
+
+func (d *Diagnostics) RuntimeStats() map[string]interface{} {
+
+	var memStats runtime.MemStats
+
+	runtime.ReadMemStats(&memStats)
+
+	return map[string]interface{}{
+		"go_version":      runtime.Version(),
+		"goroutines":      runtime.NumGoroutine(),
+		"cpus":            runtime.NumCPU(),
+		"gomaxprocs":      runtime.GOMAXPROCS(0),
+		"uptime":          time.Since(d.started).String(),
+		"heap_alloc":      memStats.HeapAlloc,
+		"heap_inuse":      memStats.HeapInuse,
+		"heap_objects":    memStats.HeapObjects,
+		"sys":             memStats.Sys,
+		"total_alloc":     memStats.TotalAlloc,
+		"num_gc":          memStats.NumGC,
+		"pause_total":     time.Duration(memStats.PauseTotalNs).String(),
+		"gc_cpu_fraction": memStats.GCCPUFraction,
+	}
+
+}
+
+func (d *Diagnostics) Configuration() map[string]interface{} {
+
+	if d.configuration == nil {
+		return nil
+	}
+
+	return d.redact(d.configuration.Load().AllSettings())
+
+}
+
+func (d *Diagnostics) redact(settings map[string]interface{}) map[string]interface{} {
+
+	redacted := make(map[string]interface{}, len(settings))
+
+	for key, value := range settings {
+
+		if d.sensitive(key) {
+			redacted[key] = RedactedValue
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			redacted[key] = d.redact(nested)
+			continue
+		}
+
+		redacted[key] = value
+
+	}
+
+	return redacted
+
+}
+
+func (d *Diagnostics) sensitive(key string) bool {
+
+	key = strings.ToLower(key)
+
+	for _, redactedKey := range d.redactedKeys {
+		if strings.Contains(key, strings.ToLower(redactedKey)) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func (d *Diagnostics) ProbeStatus() map[string]probes.ProbeResult {
+
+	if d.probes == nil {
+		return nil
+	}
+
+	return map[string]probes.ProbeResult{
+		probes.LivenessProbe:  d.probes.Status(probes.LivenessProbe),
+		probes.ReadinessProbe: d.probes.Status(probes.ReadinessProbe),
+	}
+
+}
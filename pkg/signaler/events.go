@@ -0,0 +1,136 @@
+package signaler
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+const (
+	SuccessOutcome = "success"
+	ErrorOutcome   = "error"
+	TimeoutOutcome = "timeout"
+)
+
+var (
+	DefaultHandlerDurationBuckets = []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60}
+)
+
+func WithMetrics(metricService metrics.MetricService) SignalerOption {
+	return func(s *Signaler) {
+		s.metricService = metricService
+	}
+}
+
+func WithMetricsNamespace(namespace string) SignalerOption {
+	return func(s *Signaler) {
+		s.metricsNamespace = namespace
+	}
+}
+
+func (s *Signaler) registerMetrics() {
+
+	if s.metricService == nil {
+		return
+	}
+
+	s.signalsReceived = s.metricService.Counter(
+		metrics.WithNamespace(s.metricsNamespace),
+		metrics.WithSubsystem("signaler"),
+		metrics.WithName("signals_received_total"),
+		metrics.WithHelp("Total number of operating system signals received."),
+		metrics.WithLabels([]string{"signal"}),
+	)
+
+	s.handlerDuration = s.metricService.Histogram(
+		metrics.WithNamespace(s.metricsNamespace),
+		metrics.WithSubsystem("signaler"),
+		metrics.WithName("handler_duration_seconds"),
+		metrics.WithHelp("Duration of signal handlers in seconds."),
+		metrics.WithBuckets(DefaultHandlerDurationBuckets),
+		metrics.WithLabels([]string{"signal", "handler", "outcome"}),
+	)
+
+}
+
+func (s *Signaler) recordSignal(sig os.Signal) {
+
+	if s.signalsReceived != nil {
+		s.signalsReceived.WithLabels("signal", sig.String()).Inc()
+	}
+
+	if s.logging != nil {
+		s.logging.Info("received signal",
+			"signal", sig.String(),
+			"shutdown", s.isShutdownSignal(sig),
+			"shutting_down", s.ShuttingDown(),
+		)
+	}
+
+}
+
+func (s *Signaler) recordDroppedSignal(sig os.Signal) {
+
+	if s.logging != nil {
+		s.logging.Warn("dropped signal because the handler queue is full", "signal", sig.String())
+	}
+
+}
+
+func (s *Signaler) recordHandler(sig os.Signal, h *registeredHandler, duration time.Duration, err error) {
+
+	outcome := SuccessOutcome
+
+	switch {
+	case errors.Is(err, ErrHandlerTimeout):
+		outcome = TimeoutOutcome
+	case err != nil:
+		outcome = ErrorOutcome
+	}
+
+	if s.handlerDuration != nil {
+		s.handlerDuration.WithLabels(
+			"signal", sig.String(),
+			"handler", h.name,
+			"outcome", outcome,
+		).ObserveDuration(duration)
+	}
+
+	if s.logging == nil {
+		return
+	}
+
+	if err != nil {
+		s.logging.Error("signal handler failed",
+			"signal", sig.String(),
+			"handler", h.name,
+			"priority", h.priority,
+			"outcome", outcome,
+			"duration", duration.String(),
+			"error", err.Error(),
+		)
+		return
+	}
+
+	s.logging.Info("signal handler finished",
+		"signal", sig.String(),
+		"handler", h.name,
+		"priority", h.priority,
+		"outcome", outcome,
+		"duration", duration.String(),
+	)
+
+}
+
+func (s *Signaler) recordShutdown(cause error) {
+
+	if s.logging != nil {
+		s.logging.Info("shutdown started",
+			"cause", cause.Error(),
+			"deadline", s.deadline.String(),
+		)
+	}
+
+}
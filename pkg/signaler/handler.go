@@ -0,0 +1,244 @@
+package signaler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	DefaultHandlerPriority = 0
+)
+
+type HandlerError struct {
+	Name   string
+	Signal os.Signal
+	Err    error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("signal handler %s for %s failed: %s", e.Name, e.Signal, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+var ErrHandlerTimeout = errors.New("handler timed out")
+
+var ErrRestarted = errors.New("restarted into a replacement process")
+
+type Restarter interface {
+	Restart() error
+}
+
+type HandlerOption func(*registeredHandler)
+
+type registeredHandler struct {
+	name     string
+	priority int
+	group    int
+	order    int
+	timeout  time.Duration
+	async    bool
+	running  atomic.Bool
+	handler  SignalHandler
+}
+
+func WithHandlerName(name string) HandlerOption {
+	return func(h *registeredHandler) {
+		h.name = name
+	}
+}
+
+func WithHandlerPriority(priority int) HandlerOption {
+	return func(h *registeredHandler) {
+		h.priority = priority
+	}
+}
+
+func WithHandlerTimeout(timeout time.Duration) HandlerOption {
+	return func(h *registeredHandler) {
+		h.timeout = timeout
+	}
+}
+
+func WithHandlerAsync() HandlerOption {
+	return func(h *registeredHandler) {
+		h.async = true
+	}
+}
+
+func (s *Signaler) register(sig os.Signal, handler SignalHandler, group int, options ...HandlerOption) {
+
+	if _, ok := s.handlers[sig]; !ok {
+		s.signals = append(s.signals, sig)
+		s.handlers[sig] = nil
+	}
+
+	h := &registeredHandler{
+		priority: DefaultHandlerPriority,
+		group:    group,
+		order:    len(s.handlers[sig]),
+		handler:  handler,
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	if len(h.name) == 0 {
+		h.name = fmt.Sprintf("%s#%d", sig, h.order)
+	}
+
+	if h.async {
+		s.asyncHandlers[sig] = append(s.asyncHandlers[sig], h)
+		return
+	}
+
+	s.handlers[sig] = append(s.handlers[sig], h)
+
+}
+
+func (s *Signaler) handlerGroups(sig os.Signal) [][]*registeredHandler {
+
+	handlers := append([]*registeredHandler(nil), s.handlers[sig]...)
+
+	sort.Slice(handlers, func(i, j int) bool {
+
+		if handlers[i].priority != handlers[j].priority {
+			return handlers[i].priority < handlers[j].priority
+		}
+
+		if handlers[i].group != handlers[j].group {
+			return handlers[i].group < handlers[j].group
+		}
+
+		return handlers[i].order < handlers[j].order
+
+	})
+
+	var groups [][]*registeredHandler
+
+	for i, h := range handlers {
+
+		if i > 0 {
+			previous := handlers[i-1]
+			if previous.priority == h.priority && previous.group == h.group {
+				groups[len(groups)-1] = append(groups[len(groups)-1], h)
+				continue
+			}
+		}
+
+		groups = append(groups, []*registeredHandler{h})
+
+	}
+
+	return groups
+
+}
+
+func (s *Signaler) runHandlers(sig os.Signal, release func(), errorHandler func(error)) {
+
+	for _, group := range s.handlerGroups(sig) {
+
+		errs := make([]error, len(group))
+
+		var wg sync.WaitGroup
+
+		for i, h := range group {
+
+			wg.Add(1)
+
+			go func(i int, h *registeredHandler) {
+				defer wg.Done()
+				errs[i] = s.runHandler(sig, h, release)
+			}(i, h)
+
+		}
+
+		wg.Wait()
+
+		failed := false
+
+		for _, err := range errs {
+			if err != nil {
+				failed = true
+				errorHandler(err)
+			}
+		}
+
+		if failed && s.stopOnError {
+			if s.logging != nil {
+				s.logging.Warn("skipping remaining signal handlers after failure", "signal", sig.String())
+			}
+			return
+		}
+
+	}
+
+}
+
+func (s *Signaler) runAsyncHandlers(sig os.Signal) {
+
+	for _, h := range s.asyncHandlers[sig] {
+
+		if !h.running.CompareAndSwap(false, true) {
+			if s.logging != nil {
+				s.logging.Warn("skipped signal handler that is still running", "signal", sig.String(), "handler", h.name)
+			}
+			continue
+		}
+
+		s.wg.Add(1)
+
+		go func(h *registeredHandler) {
+			defer s.wg.Done()
+			defer h.running.Store(false)
+			s.runHandler(sig, h, func() {})
+		}(h)
+
+	}
+
+}
+
+func (s *Signaler) runHandler(sig os.Signal, h *registeredHandler, release func()) error {
+
+	start := time.Now()
+
+	result := make(chan error, 1)
+
+	go func() {
+		result <- h.handler(release)
+	}()
+
+	var err error
+
+	if h.timeout > 0 {
+
+		timer := time.NewTimer(h.timeout)
+
+		select {
+		case err = <-result:
+			timer.Stop()
+		case <-timer.C:
+			err = ErrHandlerTimeout
+		}
+
+	} else {
+		err = <-result
+	}
+
+	s.recordHandler(sig, h, time.Since(start), err)
+
+	if err != nil {
+		return &HandlerError{Name: h.name, Signal: sig, Err: err}
+	}
+
+	return nil
+
+}
@@ -0,0 +1,35 @@
+//go:build !unix
+
+package signaler
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+func WithInitMode() SignalerOption {
+	return func(s *Signaler) {}
+}
+
+func WithReapDelay(delay time.Duration) SignalerOption {
+	return func(s *Signaler) {}
+}
+
+func WithSignalForwarding(signals ...os.Signal) SignalerOption {
+	return func(s *Signaler) {}
+}
+
+func (s *Signaler) StartProcess(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
+
+func (s *Signaler) forwardSignal(sig os.Signal) {}
+
+func (s *Signaler) isReapSignal(sig os.Signal) bool {
+	return false
+}
+
+func (s *Signaler) reapChildren() {
+	s.wg.Done()
+}
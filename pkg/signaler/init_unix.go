@@ -0,0 +1,200 @@
+//go:build unix
+
+package signaler
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+func WithInitMode() SignalerOption {
+	return func(s *Signaler) {
+
+		if _, ok := s.handlers[syscall.SIGCHLD]; !ok {
+			s.signals = append(s.signals, syscall.SIGCHLD)
+			s.handlers[syscall.SIGCHLD] = nil
+		}
+
+		s.initMode = true
+
+	}
+}
+
+func WithReapDelay(delay time.Duration) SignalerOption {
+	return func(s *Signaler) {
+		s.reapDelay = delay
+	}
+}
+
+func WithSignalForwarding(signals ...os.Signal) SignalerOption {
+	return func(s *Signaler) {
+
+		for _, sig := range signals {
+
+			if _, ok := s.handlers[sig]; !ok {
+				s.signals = append(s.signals, sig)
+				s.handlers[sig] = nil
+			}
+
+			s.forwarded = append(s.forwarded, sig)
+
+		}
+
+	}
+}
+
+func (s *Signaler) StartProcess(cmd *exec.Cmd) error {
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.Setpgid = true
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	s.processMutex.Lock()
+	s.processGroups[cmd.Process.Pid] = struct{}{}
+	s.processMutex.Unlock()
+
+	return nil
+
+}
+
+func (s *Signaler) forwardSignal(sig os.Signal) {
+
+	signal, ok := sig.(syscall.Signal)
+	if !ok {
+		return
+	}
+
+	s.processMutex.Lock()
+	defer s.processMutex.Unlock()
+
+	for pgid := range s.processGroups {
+
+		err := syscall.Kill(-pgid, signal)
+
+		if errors.Is(err, syscall.ESRCH) {
+			delete(s.processGroups, pgid)
+			continue
+		}
+
+		if err != nil && s.logging != nil {
+			s.logging.Warn("failed to forward signal", "signal", sig.String(), "pgid", pgid, "error", err.Error())
+		}
+
+	}
+
+}
+
+func (s *Signaler) isReapSignal(sig os.Signal) bool {
+	return s.initMode && sig == syscall.SIGCHLD
+}
+
+func (s *Signaler) reapChildren() {
+
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.reapDelay)
+	defer ticker.Stop()
+
+	seen := make(map[int]time.Time)
+
+	for {
+
+		select {
+		case <-s.stop:
+			return
+		case <-s.reapRequests:
+		case <-ticker.C:
+		}
+
+		s.reapZombies(seen)
+
+	}
+
+}
+
+func (s *Signaler) reapZombies(seen map[int]time.Time) {
+
+	zombies, err := zombieChildren()
+	if err != nil {
+		if s.logging != nil {
+			s.logging.Warn("failed to list child processes", "error", err.Error())
+		}
+		return
+	}
+
+	now := time.Now()
+	current := make(map[int]bool, len(zombies))
+
+	for _, pid := range zombies {
+
+		current[pid] = true
+
+		s.processMutex.Lock()
+		_, managed := s.processGroups[pid]
+		s.processMutex.Unlock()
+
+		if managed {
+			continue
+		}
+
+		first, ok := seen[pid]
+		if !ok {
+			seen[pid] = now
+			continue
+		}
+
+		if now.Sub(first) < s.reapDelay {
+			continue
+		}
+
+		s.reap(pid)
+		delete(seen, pid)
+
+	}
+
+	for pid := range seen {
+		if !current[pid] {
+			delete(seen, pid)
+		}
+	}
+
+}
+
+func (s *Signaler) reap(pid int) {
+
+	var status syscall.WaitStatus
+
+	for {
+
+		reaped, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil)
+
+		if errors.Is(err, syscall.EINTR) {
+			continue
+		}
+
+		if err != nil || reaped != pid {
+			return
+		}
+
+		break
+
+	}
+
+	if s.logging != nil {
+		s.logging.Debug("reaped orphaned child process",
+			"pid", pid,
+			"exit_status", status.ExitStatus(),
+			"signaled", status.Signaled(),
+		)
+	}
+
+}
@@ -0,0 +1,50 @@
+package signaler
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+func zombieChildren() ([]int, error) {
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	parent := os.Getpid()
+
+	var zombies []int
+
+	for _, entry := range entries {
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		stat, err := os.ReadFile("/proc/" + entry.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+
+		end := strings.LastIndexByte(string(stat), ')')
+		if end < 0 {
+			continue
+		}
+
+		fields := strings.Fields(string(stat[end+1:]))
+		if len(fields) < 2 || fields[0] != "Z" {
+			continue
+		}
+
+		if ppid, err := strconv.Atoi(fields[1]); err == nil && ppid == parent {
+			zombies = append(zombies, pid)
+		}
+
+	}
+
+	return zombies, nil
+
+}
@@ -0,0 +1,7 @@
+//go:build unix && !linux
+
+package signaler
+
+func zombieChildren() ([]int, error) {
+	return nil, nil
+}
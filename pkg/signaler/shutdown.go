@@ -0,0 +1,47 @@
+package signaler
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"time"
+)
+
+var (
+	DefaultForcedExitCode    = 3
+	DefaultSignalBufferSize  = 8
+	DefaultDispatchQueueSize = 16
+	DefaultReapDelay         = time.Second
+)
+
+func GoroutineDump() string {
+
+	var buf bytes.Buffer
+
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return err.Error()
+	}
+
+	return buf.String()
+
+}
+
+func (s *Signaler) ForceExit(reason string) {
+
+	dump := GoroutineDump()
+
+	if s.logging != nil {
+		s.logging.Error("forcing exit",
+			"reason", reason,
+			"exit_code", s.forcedExitCode,
+			"goroutines", dump,
+		)
+		s.logging.Close()
+	} else {
+		fmt.Fprintf(os.Stderr, "forcing exit: %s (exit code %d)\n%s", reason, s.forcedExitCode, dump)
+	}
+
+	s.exit(s.forcedExitCode)
+
+}
@@ -1,14 +1,33 @@
 package signaler
 
 import (
+	"context"
+	"fmt"
 	"os"
-	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
+
+	"github.com/definancialbr/golang-container-kit/pkg/logging"
+	"github.com/definancialbr/golang-container-kit/pkg/metrics"
+)
+
+var (
+	DefaultSignals = []os.Signal{syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM}
 )
 
 type SignalerService interface {
 	WaitForSignal(func(error))
+	Context() context.Context
+}
+
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("received signal: %s", e.Signal)
 }
 
 type SignalerOption func(*Signaler)
@@ -16,91 +35,447 @@ type SignalerOption func(*Signaler)
 type SignalHandler func(func()) error
 
 type Signaler struct {
-	wg            sync.WaitGroup
-	onInterrupt   []SignalHandler
-	onHangup      []SignalHandler
-	onTermination []SignalHandler
+	wg               sync.WaitGroup
+	receiveOnce      sync.Once
+	closeOnce        sync.Once
+	stop             chan struct{}
+	sigChan          chan os.Signal
+	queueMutex       sync.Mutex
+	queue            []os.Signal
+	queued           chan struct{}
+	signals          []os.Signal
+	handlers         map[os.Signal][]*registeredHandler
+	asyncHandlers    map[os.Signal][]*registeredHandler
+	source           SignalSource
+	groups           int
+	stopOnError      bool
+	shutdownSignals  []os.Signal
+	parent           context.Context
+	ctx              context.Context
+	cancel           context.CancelCauseFunc
+	shuttingDown     atomic.Bool
+	deadline         time.Duration
+	deadlineTimer    *time.Timer
+	deadlineMutex    sync.Mutex
+	forcedExitCode   int
+	exit             func(int)
+	logging          logging.LoggingService
+	forwarded        []os.Signal
+	processMutex     sync.Mutex
+	processGroups    map[int]struct{}
+	initMode         bool
+	reapDelay        time.Duration
+	reapRequests     chan struct{}
+	metricService    metrics.MetricService
+	metricsNamespace string
+	signalsReceived  metrics.Counter
+	handlerDuration  metrics.Histogram
 }
 
-func WithOnInterrupt(handlers ...SignalHandler) SignalerOption {
+func WithOnSignal(sig os.Signal, handlers ...SignalHandler) SignalerOption {
 	return func(s *Signaler) {
-		s.onInterrupt = append(s.onInterrupt, handlers...)
+
+		if _, ok := s.handlers[sig]; !ok {
+			s.signals = append(s.signals, sig)
+			s.handlers[sig] = nil
+		}
+
+		for _, handler := range handlers {
+			s.groups++
+			s.register(sig, handler, s.groups)
+		}
+
 	}
 }
 
-func WithOnHangup(handlers ...SignalHandler) SignalerOption {
+func WithSignalHandler(sig os.Signal, handler SignalHandler, options ...HandlerOption) SignalerOption {
 	return func(s *Signaler) {
-		s.onHangup = append(s.onInterrupt, handlers...)
+		s.register(sig, handler, 0, options...)
 	}
 }
 
-func WithOnTermination(handlers ...SignalHandler) SignalerOption {
+func WithRestartOnSignal(sig os.Signal, restarter Restarter) SignalerOption {
+	return func(s *Signaler) {
+		s.register(sig, func(release func()) error {
+
+			if err := restarter.Restart(); err != nil {
+				return err
+			}
+
+			s.Shutdown(ErrRestarted)
+			release()
+
+			return nil
+
+		}, 0, WithHandlerName("restart"))
+	}
+}
+
+func WithSignalSource(source SignalSource) SignalerOption {
+	return func(s *Signaler) {
+		s.source = source
+	}
+}
+
+func WithStopOnError() SignalerOption {
+	return func(s *Signaler) {
+		s.stopOnError = true
+	}
+}
+
+func WithShutdownSignals(signals ...os.Signal) SignalerOption {
+	return func(s *Signaler) {
+		s.shutdownSignals = signals
+	}
+}
+
+func WithParentContext(ctx context.Context) SignalerOption {
+	return func(s *Signaler) {
+		s.parent = ctx
+	}
+}
+
+func WithShutdownDeadline(deadline time.Duration) SignalerOption {
+	return func(s *Signaler) {
+		s.deadline = deadline
+	}
+}
+
+func WithForcedExitCode(code int) SignalerOption {
+	return func(s *Signaler) {
+		s.forcedExitCode = code
+	}
+}
+
+func WithExitFunc(exit func(int)) SignalerOption {
+	return func(s *Signaler) {
+		s.exit = exit
+	}
+}
+
+func WithLogging(loggingService logging.LoggingService) SignalerOption {
 	return func(s *Signaler) {
-		s.onTermination = append(s.onInterrupt, handlers...)
+		s.logging = loggingService
 	}
 }
 
+func WithOnInterrupt(handlers ...SignalHandler) SignalerOption {
+	return WithOnSignal(syscall.SIGINT, handlers...)
+}
+
+func WithOnHangup(handlers ...SignalHandler) SignalerOption {
+	return WithOnSignal(syscall.SIGHUP, handlers...)
+}
+
+func WithOnTermination(handlers ...SignalHandler) SignalerOption {
+	return WithOnSignal(syscall.SIGTERM, handlers...)
+}
+
+func WithOnQuit(handlers ...SignalHandler) SignalerOption {
+	return WithOnSignal(syscall.SIGQUIT, handlers...)
+}
+
+func WithOnUser1(handlers ...SignalHandler) SignalerOption {
+	return WithOnSignal(syscall.SIGUSR1, handlers...)
+}
+
+func WithOnUser2(handlers ...SignalHandler) SignalerOption {
+	return WithOnSignal(syscall.SIGUSR2, handlers...)
+}
+
 func NewSignaler(options ...SignalerOption) *Signaler {
 
-	s := &Signaler{}
+	s := &Signaler{
+		handlers:        make(map[os.Signal][]*registeredHandler),
+		asyncHandlers:   make(map[os.Signal][]*registeredHandler),
+		processGroups:   make(map[int]struct{}),
+		stop:            make(chan struct{}),
+		queued:          make(chan struct{}, 1),
+		reapDelay:       DefaultReapDelay,
+		reapRequests:    make(chan struct{}, 1),
+		shutdownSignals: []os.Signal{syscall.SIGTERM, syscall.SIGINT},
+		parent:          context.Background(),
+		source:          OSSignalSource{},
+		forcedExitCode:  DefaultForcedExitCode,
+		exit:            os.Exit,
+	}
 
 	for _, option := range options {
 		option(s)
 	}
 
+	s.ctx, s.cancel = context.WithCancelCause(s.parent)
+
+	s.registerMetrics()
+
+	for _, sig := range append(append([]os.Signal(nil), s.shutdownSignals...), DefaultSignals...) {
+		if _, ok := s.handlers[sig]; !ok {
+			s.signals = append(s.signals, sig)
+			s.handlers[sig] = nil
+		}
+	}
+
 	return s
 
 }
 
+func (s *Signaler) Signals() []os.Signal {
+	return s.signals
+}
+
+func (s *Signaler) Context() context.Context {
+	return s.ctx
+}
+
+func (s *Signaler) Cause() error {
+	return context.Cause(s.ctx)
+}
+
+func (s *Signaler) Shutdown(cause error) {
+
+	if !s.shuttingDown.CompareAndSwap(false, true) {
+		return
+	}
+
+	s.cancel(cause)
+
+	s.recordShutdown(context.Cause(s.ctx))
+
+	if s.deadline > 0 {
+
+		s.deadlineMutex.Lock()
+		s.deadlineTimer = time.AfterFunc(s.deadline, func() {
+			s.ForceExit(fmt.Sprintf("shutdown deadline of %s exceeded", s.deadline))
+		})
+		s.deadlineMutex.Unlock()
+
+	}
+
+}
+
+func (s *Signaler) stopDeadline() {
+
+	s.deadlineMutex.Lock()
+	defer s.deadlineMutex.Unlock()
+
+	if s.deadlineTimer != nil {
+		s.deadlineTimer.Stop()
+	}
+
+}
+
+func (s *Signaler) ShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
+
+func (s *Signaler) isShutdownSignal(sig os.Signal) bool {
+	return containsSignal(s.shutdownSignals, sig)
+}
+
+func (s *Signaler) isForwardedSignal(sig os.Signal) bool {
+	return containsSignal(s.forwarded, sig)
+}
+
+func containsSignal(signals []os.Signal, sig os.Signal) bool {
+
+	for _, candidate := range signals {
+		if sig == candidate {
+			return true
+		}
+	}
+
+	return false
+
+}
+
 func (s *Signaler) WaitForSignal(errorHandler func(error)) {
 
-	sigChan := make(chan os.Signal, 1)
+	s.startReceiver()
 
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM)
+	s.dispatchSignals(errorHandler)
+
+	s.stopDeadline()
+
+}
 
-	s.wg.Add(1)
+func (s *Signaler) Close() error {
 
-	go s.waitForSignal(sigChan, errorHandler)
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
 
 	s.wg.Wait()
 
+	s.receiveOnce.Do(func() {})
+
+	if s.sigChan != nil && len(s.signals) > 0 {
+		s.source.Stop(s.sigChan)
+	}
+
+	return nil
+
+}
+
+func (s *Signaler) startReceiver() {
+	s.receiveOnce.Do(func() {
+
+		s.sigChan = make(chan os.Signal, DefaultSignalBufferSize)
+
+		if len(s.signals) > 0 {
+			s.source.Notify(s.sigChan, s.signals...)
+		}
+
+		s.wg.Add(1)
+
+		go s.receiveSignals()
+
+		if s.initMode {
+			s.wg.Add(1)
+			go s.reapChildren()
+		}
+
+	})
+}
+
+func (s *Signaler) receiveSignals() {
+
+	defer s.wg.Done()
+
+	acknowledger, _ := s.source.(SignalAcknowledger)
+
+	for {
+
+		select {
+		case <-s.stop:
+			return
+		case sig := <-s.sigChan:
+
+			s.receive(sig)
+
+			if acknowledger != nil {
+				acknowledger.Acknowledge(s.sigChan)
+			}
+
+		}
+
+	}
+
 }
 
-func (s *Signaler) waitForSignal(sigChan chan os.Signal, errorHandler func(error)) {
+func (s *Signaler) receive(sig os.Signal) {
+
+	if s.isReapSignal(sig) {
+
+		select {
+		case s.reapRequests <- struct{}{}:
+		default:
+		}
+
+		return
+
+	}
+
+	s.recordSignal(sig)
+
+	if s.isForwardedSignal(sig) {
+		s.forwardSignal(sig)
+	}
+
+	if !s.isShutdownSignal(sig) {
+		s.runAsyncHandlers(sig)
+		if len(s.handlers[sig]) > 0 {
+			s.enqueue(sig, false)
+		}
+		return
+	}
+
+	if s.ShuttingDown() {
+		s.ForceExit(fmt.Sprintf("received %s while shutting down", sig))
+		return
+	}
+
+	s.Shutdown(&SignalError{Signal: sig})
+	s.runAsyncHandlers(sig)
+	s.enqueue(sig, true)
+
+}
+
+func (s *Signaler) enqueue(sig os.Signal, always bool) {
+
+	s.queueMutex.Lock()
+
+	if !always && len(s.queue) >= DefaultDispatchQueueSize {
+		s.queueMutex.Unlock()
+		s.recordDroppedSignal(sig)
+		return
+	}
+
+	s.queue = append(s.queue, sig)
+
+	s.queueMutex.Unlock()
+
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+
+}
+
+func (s *Signaler) dequeue() (os.Signal, bool) {
+
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	if len(s.queue) == 0 {
+		return nil, false
+	}
+
+	sig := s.queue[0]
+	s.queue = s.queue[1:]
+
+	return sig, true
+
+}
+
+func (s *Signaler) dispatchSignals(errorHandler func(error)) {
+
+	done := make(chan struct{})
 
-	var handlers []SignalHandler
 	var once sync.Once
 
 	release := func() {
 		once.Do(func() {
-			close(sigChan)
+			close(done)
 		})
 	}
 
-	for sig := range sigChan {
+	for {
 
-		handlers = nil
-
-		switch sig {
-		case syscall.SIGINT:
-			handlers = s.onInterrupt
-		case syscall.SIGTERM:
-			handlers = s.onTermination
-		case syscall.SIGHUP:
-			handlers = s.onHangup
+		select {
+		case <-done:
+			return
 		default:
-			continue
 		}
 
-		for _, handler := range handlers {
-			err := handler(release)
-			if err != nil {
-				errorHandler(err)
+		sig, ok := s.dequeue()
+		if !ok {
+
+			select {
+			case <-done:
+				return
+			case <-s.queued:
 			}
+
+			continue
+
 		}
 
-	}
+		if s.isShutdownSignal(sig) && len(s.handlers[sig]) == 0 {
+			release()
+		}
 
-	s.wg.Done()
+		s.runHandlers(sig, release, errorHandler)
+
+	}
 
 }
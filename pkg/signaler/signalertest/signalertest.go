@@ -0,0 +1,213 @@
+package signalertest
+
+import (
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/definancialbr/golang-container-kit/pkg/container"
+	"github.com/definancialbr/golang-container-kit/pkg/signaler"
+)
+
+var (
+	DefaultAcknowledgementBuffer = 64
+)
+
+type subscription struct {
+	signals      []os.Signal
+	stopped      chan struct{}
+	acknowledged chan struct{}
+}
+
+type Source struct {
+	mutex         sync.Mutex
+	ready         chan struct{}
+	readyOnce     sync.Once
+	subscriptions map[chan<- os.Signal]*subscription
+	exitCodes     []int
+}
+
+func NewSource() *Source {
+
+	return &Source{
+		ready:         make(chan struct{}),
+		subscriptions: make(map[chan<- os.Signal]*subscription),
+	}
+
+}
+
+func (s *Source) Notify(c chan<- os.Signal, signals ...os.Signal) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub, ok := s.subscriptions[c]
+	if !ok {
+		sub = &subscription{
+			stopped:      make(chan struct{}),
+			acknowledged: make(chan struct{}, DefaultAcknowledgementBuffer),
+		}
+		s.subscriptions[c] = sub
+	}
+
+	sub.signals = append(sub.signals, signals...)
+
+	s.readyOnce.Do(func() {
+		close(s.ready)
+	})
+
+}
+
+func (s *Source) Stop(c chan<- os.Signal) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if sub, ok := s.subscriptions[c]; ok {
+		close(sub.stopped)
+		delete(s.subscriptions, c)
+	}
+
+}
+
+func (s *Source) Acknowledge(c chan<- os.Signal) {
+
+	s.mutex.Lock()
+	sub, ok := s.subscriptions[c]
+	s.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	select {
+	case sub.acknowledged <- struct{}{}:
+	default:
+	}
+
+}
+
+func (s *Source) Subscribed(sig os.Signal) bool {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.subscribers(sig)) > 0
+
+}
+
+func (s *Source) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func (s *Source) Send(sig os.Signal) {
+
+	<-s.ready
+
+	s.mutex.Lock()
+	subscribers := s.subscribers(sig)
+	s.mutex.Unlock()
+
+	for c, sub := range subscribers {
+
+		select {
+		case c <- sig:
+		case <-sub.stopped:
+			continue
+		}
+
+		select {
+		case <-sub.acknowledged:
+		case <-sub.stopped:
+		}
+
+	}
+
+}
+
+func (s *Source) subscribers(sig os.Signal) map[chan<- os.Signal]*subscription {
+
+	subscribers := make(map[chan<- os.Signal]*subscription)
+
+	for c, sub := range s.subscriptions {
+		for _, subscribed := range sub.signals {
+			if subscribed == sig {
+				subscribers[c] = sub
+				break
+			}
+		}
+	}
+
+	return subscribers
+
+}
+
+func (s *Source) exit(code int) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.exitCodes = append(s.exitCodes, code)
+
+}
+
+func (s *Source) ExitCodes() []int {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]int(nil), s.exitCodes...)
+
+}
+
+func NewSignaler(source *Source, options ...signaler.SignalerOption) *signaler.Signaler {
+
+	options = append(options,
+		signaler.WithSignalSource(source),
+		signaler.WithExitFunc(source.exit),
+	)
+
+	return signaler.NewSignaler(options...)
+
+}
+
+func Shutdown(c *container.Container, source *Source, signals ...os.Signal) []error {
+
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM}
+	}
+
+	var mutex sync.Mutex
+	var errs []error
+
+	done := make(chan struct{})
+
+	c.Open()
+
+	go func() {
+
+		defer close(done)
+
+		c.Signaler.WaitForSignal(func(err error) {
+			mutex.Lock()
+			errs = append(errs, err)
+			mutex.Unlock()
+		})
+
+	}()
+
+	select {
+	case <-source.Ready():
+		for _, sig := range signals {
+			source.Send(sig)
+		}
+	case <-done:
+	}
+
+	<-done
+
+	c.Close()
+
+	return errs
+
+}
@@ -0,0 +1,190 @@
+package signalertest
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/definancialbr/golang-container-kit/pkg/container"
+	"github.com/definancialbr/golang-container-kit/pkg/signaler"
+)
+
+type recordingLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) record(msg string) {
+	l.mutex.Lock()
+	l.messages = append(l.messages, msg)
+	l.mutex.Unlock()
+}
+
+func (l *recordingLogger) Open() error                        { return nil }
+func (l *recordingLogger) Close() error                       { return nil }
+func (l *recordingLogger) Fatal(msg string, _ ...interface{}) { l.record(msg) }
+func (l *recordingLogger) Error(msg string, _ ...interface{}) { l.record(msg) }
+func (l *recordingLogger) Warn(msg string, _ ...interface{})  { l.record(msg) }
+func (l *recordingLogger) Info(msg string, _ ...interface{})  { l.record(msg) }
+func (l *recordingLogger) Debug(msg string, _ ...interface{}) { l.record(msg) }
+
+func (l *recordingLogger) contains(msg string) bool {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, message := range l.messages {
+		if message == msg {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func containsSignal(signals []os.Signal, sig os.Signal) bool {
+
+	for _, candidate := range signals {
+		if candidate == sig {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+type recorder struct {
+	mutex sync.Mutex
+	calls []string
+}
+
+func (r *recorder) handler(name string, err error, release bool) signaler.SignalHandler {
+	return func(releaseFunc func()) error {
+
+		r.mutex.Lock()
+		r.calls = append(r.calls, name)
+		r.mutex.Unlock()
+
+		if release {
+			releaseFunc()
+		}
+
+		return err
+
+	}
+}
+
+func TestShutdownRunsHandlersInOrder(t *testing.T) {
+
+	failure := errors.New("flush failed")
+
+	r := &recorder{}
+	source := NewSource()
+
+	c := container.NewContainer()
+	c.Signaler = NewSignaler(source,
+		signaler.WithOnHangup(r.handler("reload", nil, false)),
+		signaler.WithSignalHandler(syscall.SIGTERM, r.handler("close", nil, true),
+			signaler.WithHandlerName("close"),
+			signaler.WithHandlerPriority(2),
+		),
+		signaler.WithSignalHandler(syscall.SIGTERM, r.handler("flush", failure, false),
+			signaler.WithHandlerName("flush"),
+			signaler.WithHandlerPriority(1),
+		),
+	)
+
+	errs := Shutdown(c, source, syscall.SIGHUP, syscall.SIGTERM)
+
+	if expected := []string{"reload", "flush", "close"}; !reflect.DeepEqual(r.calls, expected) {
+		t.Errorf("expected handlers %v, got %v", expected, r.calls)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], failure) {
+		t.Fatalf("expected the flush failure, got %v", errs)
+	}
+
+	var handlerErr *signaler.HandlerError
+	if !errors.As(errs[0], &handlerErr) || handlerErr.Name != "flush" {
+		t.Errorf("expected a handler error for flush, got %v", errs[0])
+	}
+
+	if codes := source.ExitCodes(); len(codes) != 0 {
+		t.Errorf("expected no forced exit, got %v", codes)
+	}
+
+	var signalErr *signaler.SignalError
+	if !errors.As(c.Signaler.(*signaler.Signaler).Cause(), &signalErr) || signalErr.Signal != syscall.SIGTERM {
+		t.Errorf("expected shutdown to be caused by SIGTERM, got %v", c.Signaler.(*signaler.Signaler).Cause())
+	}
+
+}
+
+func TestShutdownForcesExitOnRepeatedSignal(t *testing.T) {
+
+	logger := &recordingLogger{}
+	r := &recorder{}
+	source := NewSource()
+
+	c := container.NewContainer()
+	c.Signaler = NewSignaler(source,
+		signaler.WithLogging(logger),
+		signaler.WithForcedExitCode(7),
+		signaler.WithOnTermination(r.handler("close", nil, true)),
+	)
+
+	errs := Shutdown(c, source, syscall.SIGTERM, syscall.SIGTERM)
+
+	if len(errs) != 0 {
+		t.Errorf("expected no handler errors, got %v", errs)
+	}
+
+	if codes := source.ExitCodes(); !reflect.DeepEqual(codes, []int{7}) {
+		t.Errorf("expected forced exit with code 7, got %v", codes)
+	}
+
+	if !logger.contains("forcing exit") {
+		t.Error("expected the forced exit to be logged")
+	}
+
+	if !reflect.DeepEqual(r.calls, []string{"close"}) {
+		t.Errorf("expected the termination handler to run once, got %v", r.calls)
+	}
+
+}
+
+func TestHangupIsIgnoredWithoutHandlers(t *testing.T) {
+
+	r := &recorder{}
+	source := NewSource()
+
+	c := container.NewContainer()
+	c.Signaler = NewSignaler(source,
+		signaler.WithOnTermination(r.handler("close", nil, true)),
+	)
+
+	if signals := c.Signaler.(*signaler.Signaler).Signals(); !containsSignal(signals, syscall.SIGHUP) {
+		t.Errorf("expected SIGHUP to be subscribed by default, got %v", signals)
+	}
+
+	errs := Shutdown(c, source, syscall.SIGHUP, syscall.SIGTERM)
+
+	if len(errs) != 0 {
+		t.Errorf("expected no handler errors, got %v", errs)
+	}
+
+	if !reflect.DeepEqual(r.calls, []string{"close"}) {
+		t.Errorf("expected only the termination handler to run, got %v", r.calls)
+	}
+
+	var signalErr *signaler.SignalError
+	if !errors.As(c.Signaler.(*signaler.Signaler).Cause(), &signalErr) || signalErr.Signal != syscall.SIGTERM {
+		t.Errorf("expected shutdown to be caused by SIGTERM, got %v", c.Signaler.(*signaler.Signaler).Cause())
+	}
+
+}
@@ -0,0 +1,25 @@
+package signaler
+
+import (
+	"os"
+	"os/signal"
+)
+
+type SignalSource interface {
+	Notify(chan<- os.Signal, ...os.Signal)
+	Stop(chan<- os.Signal)
+}
+
+type SignalAcknowledger interface {
+	Acknowledge(chan<- os.Signal)
+}
+
+type OSSignalSource struct{}
+
+func (OSSignalSource) Notify(c chan<- os.Signal, signals ...os.Signal) {
+	signal.Notify(c, signals...)
+}
+
+func (OSSignalSource) Stop(c chan<- os.Signal) {
+	signal.Stop(c)
+}